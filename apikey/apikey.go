@@ -0,0 +1,240 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+
+	keyPrefix    = "ktax_"
+	prefixLength = 12
+
+	dateLayout = "2006-01-02"
+
+	quotaKey = "apiKeyQuota"
+)
+
+// Codes of the problems the API key middleware and handlers respond with.
+const (
+	CodeMissingKey        = "missing_api_key"
+	CodeInvalidKey        = "invalid_api_key"
+	CodeRateLimitExceeded = "rate_limit_exceeded"
+	CodeInvalidKeyID      = "invalid_api_key_id"
+	CodeInvalidName       = "invalid_name"
+	CodeInvalidQuota      = "invalid_quota"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+type (
+	Key struct {
+		ID                int     `json:"id"`
+		Name              string  `json:"name"`
+		Prefix            string  `json:"prefix"`
+		Hash              string  `json:"-"`
+		RequestsPerMinute int     `json:"requestsPerMinute"`
+		RowsPerDay        int     `json:"rowsPerDay"`
+		CreatedAt         string  `json:"created_at"`
+		RevokedAt         *string `json:"revoked_at,omitempty"`
+	}
+
+	Usage struct {
+		Date     string `json:"date"`
+		Requests int    `json:"requests"`
+		Rows     int    `json:"rows"`
+	}
+
+	Storer interface {
+		APIKey(ctx context.Context, hash string) (*Key, error)
+		APIKeys(ctx context.Context) ([]Key, error)
+		CreateAPIKey(ctx context.Context, k Key) (*Key, error)
+		RevokeAPIKey(ctx context.Context, id int) error
+		// RecordUsage adds requests and rows to the key's usage on date and
+		// returns the totals for that day.
+		RecordUsage(ctx context.Context, id int, date string, requests int, rows int) (*Usage, error)
+		Usage(ctx context.Context, id int) ([]Usage, error)
+	}
+
+	// Limiter keeps a token bucket per API key.
+	Limiter struct {
+		store    Storer
+		required bool
+
+		mu      sync.Mutex
+		buckets map[int]*rate.Limiter
+	}
+
+	quota struct {
+		store Storer
+		key   *Key
+	}
+)
+
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Generate returns a new random API key.
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func today() string {
+	return time.Now().UTC().Format(dateLayout)
+}
+
+// NewLimiter rate limits requests by their API key. Requests without a key
+// are rejected only when required is set.
+func NewLimiter(s Storer, required bool) *Limiter {
+	return &Limiter{store: s, required: required, buckets: map[int]*rate.Limiter{}}
+}
+
+func (l *Limiter) bucket(k *Key) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[k.ID]
+	if !ok {
+		b = rate.NewLimiter(rate.Limit(float64(k.RequestsPerMinute)/60), k.RequestsPerMinute)
+		l.buckets[k.ID] = b
+	}
+	return b
+}
+
+func setRateLimitHeaders(c echo.Context, k *Key, b *rate.Limiter) {
+	tokens := b.Tokens()
+	reset := 0.0
+	if tokens < float64(k.RequestsPerMinute) {
+		reset = (float64(k.RequestsPerMinute) - tokens) / float64(b.Limit())
+	}
+
+	h := c.Response().Header()
+	h.Set(HeaderRateLimitLimit, strconv.Itoa(k.RequestsPerMinute))
+	h.Set(HeaderRateLimitRemaining, strconv.Itoa(int(math.Max(0, math.Floor(tokens)))))
+	h.Set(HeaderRateLimitReset, strconv.Itoa(int(math.Ceil(reset))))
+}
+
+// check counts a request made with the API key plain against its rate
+// limit. It returns the key with its bucket, or a nil key when there's no
+// key and none is required. Requests that aren't allowed fail with a
+// problem, while other errors are failed store calls.
+func (l *Limiter) check(ctx context.Context, plain string) (*Key, *rate.Limiter, error) {
+	if plain == "" {
+		if l.required {
+			return nil, nil, problem.New(http.StatusUnauthorized, CodeMissingKey, "Missing API key")
+		}
+		return nil, nil, nil
+	}
+
+	k, err := l.store.APIKey(ctx, Hash(plain))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if k == nil || k.RevokedAt != nil {
+		return nil, nil, problem.New(http.StatusUnauthorized, CodeInvalidKey, "Invalid API key")
+	}
+
+	b := l.bucket(k)
+	if !b.Allow() {
+		return k, b, problem.New(http.StatusTooManyRequests, CodeRateLimitExceeded, "Rate limit exceeded")
+	}
+
+	_, err = l.store.RecordUsage(ctx, k.ID, today(), 1, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k, b, nil
+}
+
+func (l *Limiter) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		k, b, err := l.check(c.Request().Context(), c.Request().Header.Get(HeaderAPIKey))
+		if b != nil {
+			setRateLimitHeaders(c, k, b)
+		}
+
+		var p *problem.Error
+		switch {
+		case errors.As(err, &p):
+			if p.Code == CodeRateLimitExceeded {
+				retry := math.Ceil((1 - b.Tokens()) / float64(b.Limit()))
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(retry)))
+			}
+			return problem.JSON(c, p)
+		case err != nil:
+			return storeErr(c, err)
+		case k == nil:
+			return next(c)
+		}
+
+		c.Set(quotaKey, &quota{store: l.store, key: k})
+		return next(c)
+	}
+}
+
+// errJSON responds with a problem of status, code and message.
+func errJSON(c echo.Context, status int, code, message string) error {
+	return problem.JSON(c, problem.New(status, code, message))
+}
+
+// storeErr logs a failed store call and responds to it.
+func storeErr(c echo.Context, err error) error {
+	logger.FromContext(c.Request().Context()).Error("store call failed", "error", err)
+	status, message := helper.StoreError(err)
+	return errJSON(c, status, problem.Code(status), message)
+}
+
+// ConsumeRows counts CSV rows against the daily quota of the request's API
+// key. It returns ErrQuotaExceeded, without counting them, when the rows
+// don't fit in what is left of the quota.
+func ConsumeRows(c echo.Context, rows int) error {
+	q, ok := c.Get(quotaKey).(*quota)
+	if !ok {
+		return nil
+	}
+	return q.consume(c.Request().Context(), rows)
+}
+
+func (q *quota) consume(ctx context.Context, rows int) error {
+	u, err := q.store.RecordUsage(ctx, q.key.ID, today(), 0, rows)
+	if err != nil {
+		return err
+	}
+
+	if u.Rows > q.key.RowsPerDay {
+		_, err := q.store.RecordUsage(ctx, q.key.ID, today(), 0, -rows)
+		if err != nil {
+			return err
+		}
+		return ErrQuotaExceeded
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Stub struct {
+	keys  []Key
+	usage map[int]*Usage
+	err   error
+}
+
+func (s *Stub) APIKey(ctx context.Context, hash string) (*Key, error) {
+	for i, k := range s.keys {
+		if k.Hash == hash {
+			return &s.keys[i], s.err
+		}
+	}
+	return nil, s.err
+}
+
+func (s *Stub) APIKeys(ctx context.Context) ([]Key, error) {
+	return s.keys, s.err
+}
+
+func (s *Stub) CreateAPIKey(ctx context.Context, k Key) (*Key, error) {
+	k.ID = len(s.keys) + 1
+	s.keys = append(s.keys, k)
+	return &k, s.err
+}
+
+func (s *Stub) RevokeAPIKey(ctx context.Context, id int) error {
+	revokedAt := "2024-04-22"
+	s.keys[id-1].RevokedAt = &revokedAt
+	return s.err
+}
+
+func (s *Stub) RecordUsage(ctx context.Context, id int, date string, requests int, rows int) (*Usage, error) {
+	u, ok := s.usage[id]
+	if !ok {
+		u = &Usage{Date: date}
+		s.usage[id] = u
+	}
+	u.Requests += requests
+	u.Rows += rows
+	return u, s.err
+}
+
+func (s *Stub) Usage(ctx context.Context, id int) ([]Usage, error) {
+	return []Usage{*s.usage[id]}, s.err
+}
+
+func newStub(plain string) *Stub {
+	return &Stub{
+		keys: []Key{
+			{ID: 1, Name: "partner", Hash: Hash(plain), RequestsPerMinute: 2, RowsPerDay: 5},
+		},
+		usage: map[int]*Usage{},
+	}
+}
+
+func newEcho(l *Limiter, rows int) *echo.Echo {
+	e := echo.New()
+	e.POST("/tax/calculations", func(c echo.Context) error {
+		err := ConsumeRows(c, rows)
+		if errors.Is(err, ErrQuotaExceeded) {
+			return errJSON(c, http.StatusTooManyRequests, "row_quota_exceeded", "CSV row quota exceeded")
+		}
+		return c.NoContent(http.StatusOK)
+	}, l.Middleware)
+	return e
+}
+
+func call(e *echo.Echo, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/tax/calculations", nil)
+	if key != "" {
+		req.Header.Set(HeaderAPIKey, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMiddleware(t *testing.T) {
+	plain := "ktax_test-key"
+
+	tests := []struct {
+		name     string
+		required bool
+		key      string
+		wantHttp int
+	}{
+		{name: "Missing key when required", required: true, key: "", wantHttp: http.StatusUnauthorized},
+		{name: "Missing key when optional", required: false, key: "", wantHttp: http.StatusOK},
+		{name: "Unknown key", required: true, key: "ktax_unknown", wantHttp: http.StatusUnauthorized},
+		{name: "Valid key", required: true, key: plain, wantHttp: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEcho(NewLimiter(newStub(plain), tt.required), 0)
+
+			rec := call(e, tt.key)
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+		})
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	plain := "ktax_test-key"
+	stub := newStub(plain)
+	e := newEcho(NewLimiter(stub, true), 0)
+
+	wantRemaining := []string{"1", "0"}
+	for i, want := range wantRemaining {
+		rec := call(e, plain)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status code %d but got %d", i+1, http.StatusOK, rec.Code)
+		}
+
+		if got := rec.Header().Get(HeaderRateLimitLimit); got != "2" {
+			t.Errorf("request %d: expected limit 2 but got %s", i+1, got)
+		}
+
+		if got := rec.Header().Get(HeaderRateLimitRemaining); got != want {
+			t.Errorf("request %d: expected remaining %s but got %s", i+1, want, got)
+		}
+	}
+
+	rec := call(e, plain)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status code %d but got %d", http.StatusTooManyRequests, rec.Code)
+	}
+
+	if rec.Header().Get(echo.HeaderRetryAfter) == "" {
+		t.Errorf("expected Retry-After header")
+	}
+
+	if stub.usage[1].Requests != 2 {
+		t.Errorf("expected 2 requests recorded but got %d", stub.usage[1].Requests)
+	}
+}
+
+func TestRevokedKey(t *testing.T) {
+	plain := "ktax_test-key"
+	stub := newStub(plain)
+	stub.RevokeAPIKey(context.Background(), 1)
+
+	rec := call(newEcho(NewLimiter(stub, true), 0), plain)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %d but got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestRowQuota(t *testing.T) {
+	plain := "ktax_test-key"
+	stub := newStub(plain)
+	stub.keys[0].RequestsPerMinute = 10
+	e := newEcho(NewLimiter(stub, true), 3)
+
+	wantHttp := []int{http.StatusOK, http.StatusTooManyRequests}
+	for i, want := range wantHttp {
+		rec := call(e, plain)
+		if rec.Code != want {
+			t.Errorf("upload %d: expected status code %d but got %d", i+1, want, rec.Code)
+		}
+	}
+
+	if stub.usage[1].Rows != 3 {
+		t.Errorf("expected 3 rows recorded but got %d", stub.usage[1].Rows)
+	}
+}
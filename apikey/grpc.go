@@ -0,0 +1,95 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/problem"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+	quotaContextKey struct{}
+
+	// limitedStream is a server stream whose context carries the quota
+	// of its API key.
+	limitedStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+)
+
+func (s *limitedStream) Context() context.Context {
+	return s.ctx
+}
+
+// limit checks a call to a gRPC method against the rate limit of the API
+// key in its metadata, the way Middleware checks a request.
+func (l *Limiter) limit(ctx context.Context) (context.Context, error) {
+	var plain string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(strings.ToLower(HeaderAPIKey)); len(v) > 0 {
+		plain = v[0]
+	}
+
+	k, _, err := l.check(ctx, plain)
+	var p *problem.Error
+	switch {
+	case errors.As(err, &p):
+		return nil, problem.GRPC(p, i18n.ForGRPC(ctx))
+	case err != nil:
+		logger.FromContext(ctx).Error("store call failed", "error", err)
+		status, message := helper.StoreError(err)
+		return nil, problem.GRPC(problem.New(status, problem.Code(status), message), i18n.ForGRPC(ctx))
+	case k == nil:
+		return ctx, nil
+	}
+	return context.WithValue(ctx, quotaContextKey{}, &quota{store: l.store, key: k}), nil
+}
+
+// UnaryInterceptor rate limits calls to the given full method names by the
+// API key in the x-api-key metadata.
+func (l *Limiter) UnaryInterceptor(methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := l.limit(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rate limits streams to the given full method names,
+// counting each stream as one request.
+func (l *Limiter) StreamInterceptor(methods ...string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := l.limit(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &limitedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// ConsumeRowsContext is ConsumeRows for gRPC calls.
+func ConsumeRowsContext(ctx context.Context, rows int) error {
+	q, ok := ctx.Value(quotaContextKey{}).(*quota)
+	if !ok {
+		return nil
+	}
+	return q.consume(ctx, rows)
+}
@@ -0,0 +1,126 @@
+package apikey
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultRequestsPerMinute = 60
+	defaultRowsPerDay        = 10000
+)
+
+type (
+	Handler struct {
+		store Storer
+	}
+
+	KeyReq struct {
+		Name              string `json:"name"`
+		RequestsPerMinute int    `json:"requestsPerMinute"`
+		RowsPerDay        int    `json:"rowsPerDay"`
+	}
+
+	// KeyRes carries the plain API key, which is only ever shown once.
+	KeyRes struct {
+		Key
+		APIKey string `json:"apiKey"`
+	}
+)
+
+func NewHandler(s Storer) *Handler {
+	return &Handler{store: s}
+}
+
+func (r *KeyReq) validate() error {
+	if r.Name == "" {
+		return problem.Invalid(CodeInvalidName, "Invalid name", problem.Field("/name", "required", "", "Invalid name"))
+	}
+
+	if r.RequestsPerMinute == 0 {
+		r.RequestsPerMinute = defaultRequestsPerMinute
+	}
+
+	if r.RowsPerDay == 0 {
+		r.RowsPerDay = defaultRowsPerDay
+	}
+
+	if r.RequestsPerMinute < 0 {
+		return problem.Invalid(CodeInvalidQuota, "Invalid quota value", problem.Field("/requestsPerMinute", "gte", "0", "Invalid quota value"))
+	}
+
+	if r.RowsPerDay < 0 {
+		return problem.Invalid(CodeInvalidQuota, "Invalid quota value", problem.Field("/rowsPerDay", "gte", "0", "Invalid quota value"))
+	}
+	return nil
+}
+
+func (h *Handler) Keys(c echo.Context) error {
+	keys, err := h.store.APIKeys(c.Request().Context())
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+func (h *Handler) CreateKey(c echo.Context) error {
+	reqKey := KeyReq{}
+	if err := c.Bind(&reqKey); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	err := reqKey.validate()
+	if err != nil {
+		return problem.JSON(c, problem.From(err, http.StatusBadRequest))
+	}
+
+	plain, err := Generate()
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	k, err := h.store.CreateAPIKey(c.Request().Context(), Key{
+		Name:              reqKey.Name,
+		Prefix:            plain[:prefixLength],
+		Hash:              Hash(plain),
+		RequestsPerMinute: reqKey.RequestsPerMinute,
+		RowsPerDay:        reqKey.RowsPerDay,
+	})
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, &KeyRes{Key: *k, APIKey: plain})
+}
+
+func (h *Handler) RevokeKey(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, CodeInvalidKeyID, "Invalid API key id")
+	}
+
+	err = h.store.RevokeAPIKey(c.Request().Context(), id)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *Handler) KeyUsage(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, CodeInvalidKeyID, "Invalid API key id")
+	}
+
+	usage, err := h.store.Usage(c.Request().Context(), id)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, usage)
+}
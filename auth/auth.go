@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	RoleViewer   = "viewer"
+	RoleEditor   = "editor"
+	RoleApprover = "approver"
+)
+
+// ranks orders the roles so that a role is granted everything the roles
+// below it are.
+var ranks = map[string]int{
+	RoleViewer:   1,
+	RoleEditor:   2,
+	RoleApprover: 3,
+}
+
+const userKey = "adminUser"
+
+// dummyHash is compared against when the username doesn't exist, so that
+// unknown users take as long to reject as wrong passwords.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+
+type (
+	User struct {
+		ID           int    `json:"id"`
+		Username     string `json:"username"`
+		Role         string `json:"role"`
+		PasswordHash string `json:"-"`
+		CreatedAt    string `json:"created_at"`
+	}
+
+	Storer interface {
+		AdminUser(ctx context.Context, username string) (*User, error)
+		AdminUsers(ctx context.Context) ([]User, error)
+		CreateAdminUser(ctx context.Context, u User) (*User, error)
+		DeleteAdminUser(ctx context.Context, username string) error
+	}
+)
+
+func IsRole(role string) bool {
+	_, ok := ranks[role]
+	return ok
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Authenticate returns the admin user with the given credentials, or nil
+// when they don't match.
+func Authenticate(ctx context.Context, s Storer, username, password string) (*User, error) {
+	u, err := s.AdminUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := dummyHash
+	if u != nil {
+		hash = []byte(u.PasswordHash)
+	}
+
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil || u == nil {
+		return nil, nil
+	}
+	return u, nil
+}
+
+// BasicAuth authenticates admin users stored in s.
+func BasicAuth(s Storer) echo.MiddlewareFunc {
+	return middleware.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
+		u, err := Authenticate(c.Request().Context(), s, username, password)
+		if err != nil {
+			return false, storeErr(c, err)
+		}
+
+		if u == nil {
+			return false, errJSON(c, http.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized")
+		}
+
+		SetUser(c, u)
+		return true, nil
+	})
+}
+
+// Require allows only admin users with at least the given role.
+func Require(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			u := CurrentUser(c)
+			if u == nil {
+				return errJSON(c, http.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized")
+			}
+
+			if ranks[u.Role] < ranks[role] {
+				return errJSON(c, http.StatusForbidden, problem.CodeForbidden, "Forbidden")
+			}
+			return next(c)
+		}
+	}
+}
+
+// errJSON responds with a problem of status, code and message.
+func errJSON(c echo.Context, status int, code, message string) error {
+	return problem.JSON(c, problem.New(status, code, message))
+}
+
+// storeErr logs a failed store call and responds to it.
+func storeErr(c echo.Context, err error) error {
+	logger.FromContext(c.Request().Context()).Error("store call failed", "error", err)
+	status, message := helper.StoreError(err)
+	return errJSON(c, status, problem.Code(status), message)
+}
+
+// SetUser sets the authenticated admin user of the request.
+func SetUser(c echo.Context, u *User) {
+	c.Set(userKey, u)
+}
+
+// CurrentUser returns the authenticated admin user of the request.
+func CurrentUser(c echo.Context) *User {
+	u, _ := c.Get(userKey).(*User)
+	return u
+}
+
+// Bootstrap creates the first admin user as an approver when there is no
+// admin user yet.
+func Bootstrap(ctx context.Context, s Storer, username, password string) error {
+	users, err := s.AdminUsers(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(users) > 0 || username == "" || password == "" {
+		return nil
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.CreateAdminUser(ctx, User{Username: username, Role: RoleApprover, PasswordHash: hash})
+	return err
+}
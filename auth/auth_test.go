@@ -0,0 +1,273 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type Stub struct {
+	users []User
+	err   error
+}
+
+func (s *Stub) AdminUser(ctx context.Context, username string) (*User, error) {
+	for i, u := range s.users {
+		if u.Username == username {
+			return &s.users[i], s.err
+		}
+	}
+	return nil, s.err
+}
+
+func (s *Stub) AdminUsers(ctx context.Context) ([]User, error) {
+	return s.users, s.err
+}
+
+func (s *Stub) CreateAdminUser(ctx context.Context, u User) (*User, error) {
+	u.ID = len(s.users) + 1
+	s.users = append(s.users, u)
+	return &u, s.err
+}
+
+func (s *Stub) DeleteAdminUser(ctx context.Context, username string) error {
+	for i, u := range s.users {
+		if u.Username == username {
+			s.users = append(s.users[:i], s.users[i+1:]...)
+		}
+	}
+	return s.err
+}
+
+func newUser(t *testing.T, username, password, role string) User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return User{Username: username, Role: role, PasswordHash: string(hash)}
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		role     string
+		wantHttp int
+	}{
+		{
+			name:     "Wrong password",
+			username: "editor",
+			password: "wrong",
+			role:     RoleViewer,
+			wantHttp: http.StatusUnauthorized,
+		},
+		{
+			name:     "Unknown user",
+			username: "nobody",
+			password: "editor!",
+			role:     RoleViewer,
+			wantHttp: http.StatusUnauthorized,
+		},
+		{
+			name:     "Editor can access viewer route",
+			username: "editor",
+			password: "editor!",
+			role:     RoleViewer,
+			wantHttp: http.StatusOK,
+		},
+		{
+			name:     "Editor can access editor route",
+			username: "editor",
+			password: "editor!",
+			role:     RoleEditor,
+			wantHttp: http.StatusOK,
+		},
+		{
+			name:     "Editor can't access approver route",
+			username: "editor",
+			password: "editor!",
+			role:     RoleApprover,
+			wantHttp: http.StatusForbidden,
+		},
+	}
+
+	stub := &Stub{users: []User{newUser(t, "editor", "editor!", RoleEditor)}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.Use(BasicAuth(stub))
+			e.GET("/admin", func(c echo.Context) error {
+				return c.String(http.StatusOK, CurrentUser(c).Username)
+			}, Require(tt.role))
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.SetBasicAuth(tt.username, tt.password)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+		})
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	stub := &Stub{}
+
+	err := Bootstrap(context.Background(), stub, "adminTax", "admin!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = Bootstrap(context.Background(), stub, "other", "other!")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stub.users) != 1 || stub.users[0].Username != "adminTax" || stub.users[0].Role != RoleApprover {
+		t.Fatalf("expected a single approver adminTax but got %v", stub.users)
+	}
+
+	u, err := Authenticate(context.Background(), stub, "adminTax", "admin!")
+	if err != nil || u == nil {
+		t.Errorf("expected bootstrapped user to authenticate but got %v, %v", u, err)
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqBody  UserReq
+		wantHttp int
+	}{
+		{
+			name:     "Create viewer",
+			reqBody:  UserReq{Username: "viewer", Password: "viewer-password", Role: RoleViewer},
+			wantHttp: http.StatusCreated,
+		},
+		{
+			name:     "Username already exists",
+			reqBody:  UserReq{Username: "viewer", Password: "viewer-password", Role: RoleViewer},
+			wantHttp: http.StatusConflict,
+		},
+		{
+			name:     "Password too short",
+			reqBody:  UserReq{Username: "editor", Password: "short", Role: RoleEditor},
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid role",
+			reqBody:  UserReq{Username: "root", Password: "root-password", Role: "root"},
+			wantHttp: http.StatusBadRequest,
+		},
+	}
+
+	e := echo.New()
+	e.POST("/admin/users", NewHandler(&Stub{}).CreateUser)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBodyStr, err := json.Marshal(tt.reqBody)
+			if err != nil {
+				t.Errorf("error marshalling json: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users", strings.NewReader(string(reqBodyStr)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+
+			if strings.Contains(rec.Body.String(), tt.reqBody.Password) {
+				t.Errorf("expected password not to be in response but got %s", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		wantCode      codes.Code
+		wantUser      string
+	}{
+		{
+			name:     "Method open to everyone",
+			method:   "/ktax.v1.TaxService/CalculateTax",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Missing credentials",
+			method:   "/ktax.v1.TaxService/UpdateDeduction",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:          "Wrong password",
+			method:        "/ktax.v1.TaxService/UpdateDeduction",
+			authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte("editor:wrong")),
+			wantCode:      codes.Unauthenticated,
+		},
+		{
+			name:          "Role too low",
+			method:        "/ktax.v1.TaxService/UpdateDeduction",
+			authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte("viewer:viewer!")),
+			wantCode:      codes.PermissionDenied,
+		},
+		{
+			name:          "Editor can update",
+			method:        "/ktax.v1.TaxService/UpdateDeduction",
+			authorization: "Basic " + base64.StdEncoding.EncodeToString([]byte("editor:editor!")),
+			wantCode:      codes.OK,
+			wantUser:      "editor",
+		},
+	}
+
+	stub := &Stub{users: []User{
+		newUser(t, "viewer", "viewer!", RoleViewer),
+		newUser(t, "editor", "editor!", RoleEditor),
+	}}
+	interceptor := UnaryInterceptor(Basic(stub), map[string]string{"/ktax.v1.TaxService/UpdateDeduction": RoleEditor})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+
+			var gotUser string
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				if u := UserFromContext(ctx); u != nil {
+					gotUser = u.Username
+				}
+				return nil, nil
+			})
+
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("expected code %s but got %s", tt.wantCode, got)
+			}
+			if gotUser != tt.wantUser {
+				t.Errorf("expected user %q but got %q", tt.wantUser, gotUser)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/problem"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+	// Authenticator returns the admin user an authorization header value
+	// belongs to, or nil when it doesn't authenticate anyone.
+	Authenticator func(ctx context.Context, authorization string) (*User, error)
+
+	userContextKey struct{}
+
+	// authorizedStream is a server stream whose context carries the admin
+	// user.
+	authorizedStream struct {
+		grpc.ServerStream
+		ctx context.Context
+	}
+)
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// Basic authenticates admin users stored in s with Basic credentials.
+func Basic(s Storer) Authenticator {
+	return func(ctx context.Context, authorization string) (*User, error) {
+		encoded, ok := strings.CutPrefix(authorization, "Basic ")
+		if !ok {
+			return nil, nil
+		}
+
+		b, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, nil
+		}
+
+		username, password, ok := strings.Cut(string(b), ":")
+		if !ok {
+			return nil, nil
+		}
+		return Authenticate(ctx, s, username, password)
+	}
+}
+
+// Bearer authenticates admin users with tokens verified by v.
+func Bearer(v *Verifier) Authenticator {
+	return func(ctx context.Context, authorization string) (*User, error) {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok || token == "" {
+			return nil, nil
+		}
+
+		u, err := v.Verify(token)
+		if err != nil {
+			return nil, nil
+		}
+		return u, nil
+	}
+}
+
+// UserFromContext returns the admin user authenticated for a gRPC call.
+func UserFromContext(ctx context.Context) *User {
+	u, _ := ctx.Value(userContextKey{}).(*User)
+	return u
+}
+
+// authorize authenticates the admin user of a call to method when roles
+// names the role the method requires.
+func authorize(ctx context.Context, authn Authenticator, roles map[string]string, method string) (context.Context, error) {
+	role, ok := roles[method]
+	if !ok {
+		return ctx, nil
+	}
+
+	var authorization string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		authorization = v[0]
+	}
+
+	u, err := authn(ctx, authorization)
+	if err != nil {
+		logger.FromContext(ctx).Error("store call failed", "error", err)
+		status, message := helper.StoreError(err)
+		return nil, problem.GRPC(problem.New(status, problem.Code(status), message), i18n.ForGRPC(ctx))
+	}
+
+	if u == nil {
+		return nil, problem.GRPC(problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized"), i18n.ForGRPC(ctx))
+	}
+
+	if ranks[u.Role] < ranks[role] {
+		return nil, problem.GRPC(problem.New(http.StatusForbidden, problem.CodeForbidden, "Forbidden"), i18n.ForGRPC(ctx))
+	}
+	return context.WithValue(ctx, userContextKey{}, u), nil
+}
+
+// UnaryInterceptor allows only admin users authenticated by the
+// authorization metadata to call the methods in roles, which maps full
+// method names to the role they require. Other methods are let through.
+func UnaryInterceptor(authn Authenticator, roles map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authorize(ctx, authn, roles, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is UnaryInterceptor for streaming methods.
+func StreamInterceptor(authn Authenticator, roles map[string]string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), authn, roles, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+	}
+}
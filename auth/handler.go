@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/labstack/echo/v4"
+)
+
+// minPasswordLength applies to admin users created through the API.
+const minPasswordLength = 8
+
+// Codes of the problems the admin user handlers respond with.
+const (
+	CodeInvalidUsername = "invalid_username"
+	CodeInvalidPassword = "invalid_password"
+	CodeInvalidRole     = "invalid_role"
+	CodeUsernameTaken   = "username_taken"
+	CodeSelfDelete      = "self_delete"
+	CodeUserNotFound    = "user_not_found"
+)
+
+type (
+	Handler struct {
+		store Storer
+	}
+
+	UserReq struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Role     string `json:"role"`
+	}
+)
+
+func NewHandler(s Storer) *Handler {
+	return &Handler{store: s}
+}
+
+func (r *UserReq) validate() error {
+	if r.Username == "" {
+		return problem.Invalid(CodeInvalidUsername, "Invalid username", problem.Field("/username", "required", "", "Invalid username"))
+	}
+
+	if len(r.Password) < minPasswordLength {
+		message := "Password must be at least %d characters"
+		field := problem.Field("/password", "min", strconv.Itoa(minPasswordLength), message, minPasswordLength)
+		return problem.Invalid(CodeInvalidPassword, message, field).With(minPasswordLength)
+	}
+
+	if !IsRole(r.Role) {
+		roles := RoleViewer + " " + RoleEditor + " " + RoleApprover
+		return problem.Invalid(CodeInvalidRole, "Invalid role", problem.Field("/role", "oneof", roles, "Invalid role"))
+	}
+	return nil
+}
+
+func (h *Handler) Users(c echo.Context) error {
+	users, err := h.store.AdminUsers(c.Request().Context())
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, users)
+}
+
+func (h *Handler) CreateUser(c echo.Context) error {
+	reqUser := UserReq{}
+	if err := c.Bind(&reqUser); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	err := reqUser.validate()
+	if err != nil {
+		return problem.JSON(c, problem.From(err, http.StatusBadRequest))
+	}
+
+	exist, err := h.store.AdminUser(c.Request().Context(), reqUser.Username)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	if exist != nil {
+		return errJSON(c, http.StatusConflict, CodeUsernameTaken, "Username already exists")
+	}
+
+	hash, err := HashPassword(reqUser.Password)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	u, err := h.store.CreateAdminUser(c.Request().Context(), User{Username: reqUser.Username, Role: reqUser.Role, PasswordHash: hash})
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, u)
+}
+
+func (h *Handler) DeleteUser(c echo.Context) error {
+	username := c.Param("username")
+	if u := CurrentUser(c); u != nil && u.Username == username {
+		return errJSON(c, http.StatusBadRequest, CodeSelfDelete, "Can't delete yourself")
+	}
+
+	exist, err := h.store.AdminUser(c.Request().Context(), username)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	if exist == nil {
+		return errJSON(c, http.StatusNotFound, CodeUserNotFound, "Admin user not found")
+	}
+
+	err = h.store.DeleteAdminUser(c.Request().Context(), username)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,299 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// jwksRefresh is how long fetched signing keys are trusted before the
+	// JWKS URL is fetched again.
+	jwksRefresh = 10 * time.Minute
+
+	// jwksMinRefresh limits how often an unknown key ID refetches the JWKS.
+	jwksMinRefresh = 30 * time.Second
+)
+
+var signingMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+type (
+	JWTConfig struct {
+		JWKSURL   string
+		KeyFile   string
+		Issuer    string
+		Audience  string
+		RoleClaim string
+		// RoleMap maps role claim values to admin roles. Claim values that
+		// are already admin roles are accepted when it's empty.
+		RoleMap map[string]string
+	}
+
+	Verifier struct {
+		cfg    JWTConfig
+		client *http.Client
+
+		mu        sync.Mutex
+		key       crypto.PublicKey
+		keys      map[string]crypto.PublicKey
+		fetchedAt time.Time
+	}
+
+	jwk struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+// NewVerifier verifies tokens with the public key in cfg.KeyFile, or with
+// the keys published at cfg.JWKSURL.
+func NewVerifier(cfg JWTConfig) (*Verifier, error) {
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = "roles"
+	}
+
+	v := &Verifier{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+	if cfg.KeyFile != "" {
+		b, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		v.key, err = parsePublicKey(b)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	if cfg.JWKSURL == "" {
+		return nil, fmt.Errorf("jwt needs a key file or a JWKS URL")
+	}
+	return v, nil
+}
+
+func parsePublicKey(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM public key")
+	}
+
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		return cert.PublicKey, nil
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+func (v *Verifier) fetchKeys() error {
+	res, err := v.client.Get(v.cfg.JWKSURL)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching JWKS: %s", res.Status)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&set)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+func (v *Verifier) keyFunc(t *jwt.Token) (interface{}, error) {
+	if v.key != nil {
+		return v.key, nil
+	}
+
+	kid, _ := t.Header["kid"].(string)
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key, ok := v.keys[kid]
+	stale := time.Since(v.fetchedAt) > jwksRefresh
+	if ok && !stale {
+		return key, nil
+	}
+
+	if stale || time.Since(v.fetchedAt) > jwksMinRefresh {
+		err := v.fetchKeys()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, ok = v.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return key, nil
+}
+
+// role returns the highest admin role granted by the token's role claim.
+func (v *Verifier) role(claims jwt.MapClaims) string {
+	var values []string
+	switch c := claims[v.cfg.RoleClaim].(type) {
+	case string:
+		values = strings.Fields(c)
+	case []interface{}:
+		for _, r := range c {
+			if s, ok := r.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	role := ""
+	for _, value := range values {
+		r := value
+		if len(v.cfg.RoleMap) > 0 {
+			r = v.cfg.RoleMap[value]
+		}
+
+		if IsRole(r) && ranks[r] > ranks[role] {
+			role = r
+		}
+	}
+	return role
+}
+
+// Verify returns the admin user the token was issued to.
+func (v *Verifier) Verify(token string) (*User, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+	}
+	if v.cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.cfg.Issuer))
+	}
+	if v.cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(v.cfg.Audience))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, v.keyFunc, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	username, _ := claims["preferred_username"].(string)
+	if username == "" {
+		username, _ = claims.GetSubject()
+	}
+
+	return &User{Username: username, Role: v.role(claims)}, nil
+}
+
+// JWT authenticates admin users with bearer tokens.
+func JWT(v *Verifier) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Request().Header.Get(echo.HeaderAuthorization)
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || token == "" {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return errJSON(c, http.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized")
+			}
+
+			u, err := v.Verify(token)
+			if err != nil {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return errJSON(c, http.StatusUnauthorized, problem.CodeUnauthorized, "Unauthorized")
+			}
+
+			if u.Role == "" {
+				return errJSON(c, http.StatusForbidden, problem.CodeForbidden, "Forbidden")
+			}
+
+			SetUser(c, u)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+)
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writeKeyFile(t *testing.T, key *rsa.PrivateKey) string {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(t.TempDir(), "public.pem")
+	err = os.WriteFile(name, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func newJWKSServer(t *testing.T, kid string, key *rsa.PrivateKey) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{
+				{
+					"kid": kid,
+					"kty": "RSA",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func sign(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func claims(roles []string, exp time.Duration) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":                "https://sso.example.com",
+		"aud":                "k-tax",
+		"sub":                "1234",
+		"preferred_username": "somchai",
+		"roles":              roles,
+		"exp":                time.Now().Add(exp).Unix(),
+	}
+}
+
+func TestJWT(t *testing.T) {
+	key := newKey(t)
+	otherKey := newKey(t)
+
+	tests := []struct {
+		name     string
+		token    string
+		role     string
+		wantHttp int
+		wantUser string
+	}{
+		{
+			name:     "Missing bearer token",
+			token:    "",
+			role:     RoleViewer,
+			wantHttp: http.StatusUnauthorized,
+		},
+		{
+			name:     "Approver claim can access approver route",
+			token:    sign(t, key, "k1", claims([]string{"ktax-approver"}, time.Hour)),
+			role:     RoleApprover,
+			wantHttp: http.StatusOK,
+			wantUser: "somchai",
+		},
+		{
+			name:     "Highest mapped role is used",
+			token:    sign(t, key, "k1", claims([]string{"ktax-viewer", "ktax-editor"}, time.Hour)),
+			role:     RoleEditor,
+			wantHttp: http.StatusOK,
+			wantUser: "somchai",
+		},
+		{
+			name:     "Viewer claim can't access editor route",
+			token:    sign(t, key, "k1", claims([]string{"ktax-viewer"}, time.Hour)),
+			role:     RoleEditor,
+			wantHttp: http.StatusForbidden,
+		},
+		{
+			name:     "Unmapped role claim is forbidden",
+			token:    sign(t, key, "k1", claims([]string{"approver"}, time.Hour)),
+			role:     RoleViewer,
+			wantHttp: http.StatusForbidden,
+		},
+		{
+			name:     "Expired token",
+			token:    sign(t, key, "k1", claims([]string{"ktax-approver"}, -time.Hour)),
+			role:     RoleViewer,
+			wantHttp: http.StatusUnauthorized,
+		},
+		{
+			name:     "Token signed with another key",
+			token:    sign(t, otherKey, "k1", claims([]string{"ktax-approver"}, time.Hour)),
+			role:     RoleViewer,
+			wantHttp: http.StatusUnauthorized,
+		},
+		{
+			name: "Token from another issuer",
+			token: sign(t, key, "k1", jwt.MapClaims{
+				"iss":   "https://evil.example.com",
+				"aud":   "k-tax",
+				"sub":   "1234",
+				"roles": []string{"ktax-approver"},
+				"exp":   time.Now().Add(time.Hour).Unix(),
+			}),
+			role:     RoleViewer,
+			wantHttp: http.StatusUnauthorized,
+		},
+	}
+
+	cfg := JWTConfig{
+		Issuer:   "https://sso.example.com",
+		Audience: "k-tax",
+		RoleMap: map[string]string{
+			"ktax-viewer":   RoleViewer,
+			"ktax-editor":   RoleEditor,
+			"ktax-approver": RoleApprover,
+		},
+	}
+
+	keyFile := cfg
+	keyFile.KeyFile = writeKeyFile(t, key)
+
+	jwks := cfg
+	jwks.JWKSURL = newJWKSServer(t, "k1", key).URL
+
+	for _, source := range []struct {
+		name string
+		cfg  JWTConfig
+	}{
+		{name: "Key file", cfg: keyFile},
+		{name: "JWKS", cfg: jwks},
+	} {
+		v, err := NewVerifier(source.cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, tt := range tests {
+			t.Run(source.name+"/"+tt.name, func(t *testing.T) {
+				e := echo.New()
+				e.Use(JWT(v))
+				e.GET("/admin", func(c echo.Context) error {
+					return c.String(http.StatusOK, CurrentUser(c).Username)
+				}, Require(tt.role))
+
+				req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+				if tt.token != "" {
+					req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+
+				if rec.Code != tt.wantHttp {
+					t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+				}
+
+				if tt.wantUser != "" && rec.Body.String() != tt.wantUser {
+					t.Errorf("expected user %s but got %s", tt.wantUser, rec.Body.String())
+				}
+			})
+		}
+	}
+}
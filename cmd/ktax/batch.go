@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+// batch reads a CSV file in the upload-csv format, or stdin when it's
+// left out or "-", and writes the tax of every row as CSV or JSON.
+func batch(ctx context.Context, c *command, args []string) error {
+	format := c.flags.String("format", "csv", "output format, csv or json")
+	out := c.flags.String("o", "", "output `file`, stdout when empty")
+	if err := c.parse(args); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		fmt.Fprintf(c.stderr, "unknown format %q\n", *format)
+		return errUsage
+	}
+
+	in := c.stdin
+	if name := c.flags.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	taxesReq, err := tax.ReadTaxCSV(in)
+	if err != nil {
+		return err
+	}
+
+	calculator, err := c.calculator()
+	if err != nil {
+		return err
+	}
+
+	res, err := calculator.Batch(ctx, c.year, taxesReq)
+	if err != nil {
+		return err
+	}
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		c.stdout = f
+	}
+
+	if *format == "json" {
+		return c.printJSON(res)
+	}
+	return writeCSV(c.stdout, res)
+}
+
+func writeCSV(w io.Writer, res tax.TaxUploadResponseV2) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"totalIncome", "tax", "taxRefund"})
+	for _, t := range res.Taxes {
+		cw.Write([]string{t.TotalIncome, t.Tax, t.TaxRefund})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+// requestFlags are the flags a request can be given with instead of JSON
+// on stdin.
+var requestFlags = map[string]bool{"income": true, "wht": true, "donation": true, "k-receipt": true, "period": true}
+
+// request reads a tax request from the flags of c, or from the JSON of a
+// v2 request on stdin when none of them is set. -year overrides the
+// taxYear of the JSON.
+func request(c *command, args []string) (tax.TaxRequestV2, error) {
+	var (
+		req                tax.TaxRequestV2
+		donation, kReceipt float64
+	)
+	c.flags.Float64Var(&req.TotalIncome, "income", 0, "total income")
+	c.flags.Float64Var(&req.WHT, "wht", 0, "withholding tax")
+	c.flags.Float64Var(&donation, "donation", 0, "donation allowance")
+	c.flags.Float64Var(&kReceipt, "k-receipt", 0, "k-receipt allowance")
+	c.flags.StringVar(&req.Period, "period", "", "annual or half-year")
+	if err := c.parse(args); err != nil {
+		return tax.TaxRequestV2{}, err
+	}
+
+	set := map[string]bool{}
+	c.flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	fromFlags := false
+	for name := range set {
+		fromFlags = fromFlags || requestFlags[name]
+	}
+
+	if !fromFlags {
+		if err := json.NewDecoder(c.stdin).Decode(&req); err != nil {
+			return tax.TaxRequestV2{}, problem.Invalid(problem.CodeInvalidBody, "Invalid request body")
+		}
+	}
+	if set["donation"] {
+		req.Allowances = append(req.Allowances, tax.AllowanceReq{AllowanceType: "donation", Amount: donation})
+	}
+	if set["k-receipt"] {
+		req.Allowances = append(req.Allowances, tax.AllowanceReq{AllowanceType: "k-receipt", Amount: kReceipt})
+	}
+	if set["year"] {
+		req.TaxYear = c.year
+	}
+	return req, nil
+}
+
+func calc(ctx context.Context, c *command, args []string) error {
+	req, err := request(c, args)
+	if err != nil {
+		return err
+	}
+
+	calculator, err := c.calculator()
+	if err != nil {
+		return err
+	}
+
+	res, err := calculator.Tax(ctx, req, c.printer())
+	if err != nil {
+		return err
+	}
+	return c.printJSON(res)
+}
+
+func explain(ctx context.Context, c *command, args []string) error {
+	req, err := request(c, args)
+	if err != nil {
+		return err
+	}
+
+	calculator, err := c.calculator()
+	if err != nil {
+		return err
+	}
+
+	e, err := calculator.Explain(ctx, req, c.printer())
+	if err != nil {
+		return err
+	}
+
+	res := e.Response
+	w := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Tax year\t%d\n", res.TaxYear)
+	fmt.Fprintf(w, "Total income\t%s\n", res.Summary.TotalIncome)
+	for _, a := range e.Allowances {
+		fmt.Fprintf(w, "  %s (claimed %.2f)\t%.2f\n", a.Type, a.Claimed, a.Deducted)
+	}
+	fmt.Fprintf(w, "Net income\t%s\n", res.Summary.NetIncome)
+	for _, l := range e.Levels {
+		fmt.Fprintf(w, "  %s at %.0f%% on %.2f\t%.2f\n", l.Level, l.Rate*100, l.Income, l.Tax)
+	}
+	fmt.Fprintf(w, "Level tax\t%s\n", res.Summary.LevelTax)
+	fmt.Fprintf(w, "  Tax paid\t%s\n", res.Summary.TaxPaid)
+	fmt.Fprintf(w, "  Credits\t%s\n", res.Summary.Credits)
+	fmt.Fprintf(w, "Tax\t%s\n", res.Tax)
+	if s := res.Surcharge; s != nil {
+		fmt.Fprintf(w, "Tax with %d months of surcharge\t%s\n", s.Months, s.Total)
+	}
+	fmt.Fprintf(w, "Tax refund\t%s\n", res.TaxRefund)
+	return w.Flush()
+}
@@ -0,0 +1,131 @@
+// Command ktax calculates taxes with the engine of the API, without the
+// server or the database. The allowances and exchange rates are read from
+// a YAML or JSON config file, or default to the ones the migrations seed.
+//
+//	ktax calc    [flags]            calculate one tax from flags or JSON on stdin
+//	ktax batch   [flags] [file.csv] calculate every row of an upload-csv file
+//	ktax explain [flags]            show the steps of one calculation
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/memory"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+const usage = "usage: ktax [calc | batch | explain] [flags]"
+
+// errUsage is returned when the arguments don't parse. The flag package
+// has already told the user why.
+var errUsage = errors.New(usage)
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the subcommand of args and returns the exit code: 1 when the
+// calculation fails and 2 when the arguments are wrong.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	commands := map[string]func(context.Context, *command, []string) error{
+		"calc":    calc,
+		"batch":   batch,
+		"explain": explain,
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	c := newCommand(args[0], stdin, stdout, stderr)
+	err := cmd(ctx, c, args[1:])
+	if errors.Is(err, errUsage) {
+		return 2
+	}
+	if err != nil {
+		c.printErr(err)
+		return 1
+	}
+	return 0
+}
+
+// command holds the flags every subcommand shares.
+type command struct {
+	flags  *flag.FlagSet
+	config string
+	lang   string
+	year   int
+
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+func newCommand(name string, stdin io.Reader, stdout, stderr io.Writer) *command {
+	c := &command{flags: flag.NewFlagSet(name, flag.ContinueOnError), stdin: stdin, stdout: stdout, stderr: stderr}
+	c.flags.SetOutput(stderr)
+	c.flags.StringVar(&c.config, "config", "", "YAML or JSON `file` of allowances and exchange rates")
+	c.flags.StringVar(&c.lang, "lang", i18n.Default, "language of the tax levels and errors, th or en")
+	c.flags.IntVar(&c.year, "year", 0, "tax year, the last one when 0")
+	return c
+}
+
+func (c *command) parse(args []string) error {
+	if err := c.flags.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+func (c *command) printer() *i18n.Printer {
+	return i18n.NewPrinter(c.lang)
+}
+
+// calculator calculates with the config, or with the defaults when there
+// is none. YAML configs use the keys of JSON ones.
+func (c *command) calculator() (*tax.Calculator, error) {
+	if c.config == "" {
+		return tax.NewCalculator(memory.New()), nil
+	}
+
+	s, err := memory.Read(c.config)
+	if err != nil {
+		return nil, err
+	}
+	return tax.NewCalculator(s), nil
+}
+
+func (c *command) printJSON(v interface{}) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printErr prints err, and the fields a problem points at, in the
+// language of the command.
+func (c *command) printErr(err error) {
+	var p *problem.Error
+	if !errors.As(err, &p) {
+		fmt.Fprintln(c.stderr, "ktax:", err)
+		return
+	}
+
+	l := problem.Localize(p, c.printer())
+	fmt.Fprintf(c.stderr, "ktax: %s (%s)\n", l.Message, l.Code)
+	for _, f := range l.Fields {
+		fmt.Fprintf(c.stderr, "  %s: %s\n", f.Pointer, f.Message)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	csvFile := write("taxes.csv", "totalIncome,wht,donation\n500000,0,0\n600000,40000,20000\n")
+	yamlConfig := write("config.yaml", "allowances:\n  - type: personal\n    init_amount: 100000\n    min_amount: 10000\n    max_amount: 100000\n")
+	jsonConfig := write("config.json", `{"exchangeRates": [{"date": "2024-01-31", "currency": "USD", "rate": 35}]}`)
+	badConfig := write("bad.yaml", "allowances:\n  - type: rent\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOut    []string
+		wantErrOut string
+	}{
+		{
+			name:     "Calc from flags",
+			args:     []string{"calc", "-year", "2024", "-income", "500000", "-donation", "200000"},
+			wantOut:  []string{`"tax": "19000.00"`, `"taxYear": 2024`},
+			wantCode: 0,
+		},
+		{
+			name:     "Calc from JSON on stdin",
+			args:     []string{"calc", "-year", "2024"},
+			stdin:    `{"totalIncome": 500000.0, "wht": 30000.0, "allowances": []}`,
+			wantOut:  []string{`"taxRefund": "1000.00"`},
+			wantCode: 0,
+		},
+		{
+			name:     "Calc with allowances from a YAML config",
+			args:     []string{"calc", "-year", "2024", "-config", yamlConfig, "-income", "500000"},
+			wantOut:  []string{`"tax": "25000.00"`},
+			wantCode: 0,
+		},
+		{
+			name:     "Calc with exchange rates from a JSON config",
+			args:     []string{"calc", "-year", "2024", "-config", jsonConfig},
+			stdin:    `{"incomes": [{"amount": 20000, "wht": 0, "currency": "USD", "receivedDate": "2024-02-01"}], "allowances": []}`,
+			wantOut:  []string{`"totalIncome": "700000.00"`, `"rate": "35"`},
+			wantCode: 0,
+		},
+		{
+			name:       "Calc fails with the problem of an invalid request",
+			args:       []string{"calc", "-lang", "en", "-income", "-1"},
+			wantCode:   1,
+			wantErrOut: "/totalIncome: Must be at least 0",
+		},
+		{
+			name:       "Calc fails with an unknown allowance type in the config",
+			args:       []string{"calc", "-config", badConfig, "-income", "1"},
+			wantCode:   1,
+			wantErrOut: `unknown allowance type "rent"`,
+		},
+		{
+			name:     "Batch to CSV",
+			args:     []string{"batch", "-year", "2024", csvFile},
+			wantOut:  []string{"totalIncome,tax,taxRefund\n500000.00,29000.00,0.00\n600000.00,0.00,2000.00\n"},
+			wantCode: 0,
+		},
+		{
+			name:     "Batch from stdin to JSON",
+			args:     []string{"batch", "-year", "2024", "-format", "json"},
+			stdin:    "totalIncome,wht,donation\n500000,0,0\n",
+			wantOut:  []string{`"taxes": [`, `"tax": "29000.00"`},
+			wantCode: 0,
+		},
+		{
+			name:       "Batch fails with the row of an invalid value",
+			args:       []string{"batch", "-lang", "en"},
+			stdin:      "totalIncome,wht,donation\n500000,0,x\n",
+			wantCode:   1,
+			wantErrOut: "/rows/0/donation: Invalid Donation value",
+		},
+		{
+			name:     "Explain",
+			args:     []string{"explain", "-lang", "en", "-year", "2024", "-income", "500000", "-donation", "200000"},
+			wantOut:  []string{"donation (claimed 200000.00) 100000.00", "150,001 - 500,000 at 10% on 190000.00 19000.00", "Tax 19000.00"},
+			wantCode: 0,
+		},
+		{
+			name:     "Unknown subcommand",
+			args:     []string{"file"},
+			wantCode: 2,
+		},
+		{
+			name:     "Unknown format",
+			args:     []string{"batch", "-format", "xml"},
+			wantCode: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+			if code != tt.wantCode {
+				t.Errorf("expected exit code %d but got %d: %s", tt.wantCode, code, stderr.String())
+			}
+			// Collapse the columns of explain.
+			out := strings.Join(strings.Fields(stdout.String()), " ")
+			for _, want := range tt.wantOut {
+				if !strings.Contains(out, strings.Join(strings.Fields(want), " ")) {
+					t.Errorf("expected output to contain %q but got %s", want, stdout.String())
+				}
+			}
+			if !strings.Contains(stderr.String(), tt.wantErrOut) {
+				t.Errorf("expected errors to contain %q but got %s", tt.wantErrOut, stderr.String())
+			}
+		})
+	}
+}
+
+func TestBatchOutputFile(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "taxes.json")
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"batch", "-year", "2024", "-format", "json", "-o", out}, strings.NewReader("totalIncome,wht,donation\n500000,0,0\n"), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0 but got %d: %s", code, stderr.String())
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var res tax.TaxUploadResponseV2
+	if err := json.Unmarshal(b, &res); err != nil {
+		t.Fatalf("error unmarshalling json: %v", err)
+	}
+	if len(res.Taxes) != 1 || res.Taxes[0].Tax != "29000.00" {
+		t.Errorf("expected the tax of one row to be 29000.00 but got %v", res.Taxes)
+	}
+}
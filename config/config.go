@@ -1,39 +1,223 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type (
 	Config struct {
 		DB          DB
+		Store       Store
 		Server      Server
 		Credentials Credentials
+		Auth        Auth
+		APIKeys     APIKeys
+		Approval    Approval
+		V1          Deprecation
+		Tracing     Tracing
+		Log         Log
 	}
 
+	// DB sets how long a single query may take and how the connection
+	// pool is sized. Driver is "postgres", or "sqlite" when STORE_DRIVER
+	// is, and then Url is the path of the database file.
 	DB struct {
-		Url string
+		Driver          string
+		Url             string
+		AutoMigrate     bool
+		ConnectTimeout  time.Duration
+		QueryTimeout    time.Duration
+		MaxOpenConns    int
+		MaxIdleConns    int
+		ConnMaxLifetime time.Duration
+	}
+
+	// Store selects what keeps the allowances, admin users and API keys:
+	// "postgres" or "sqlite", the database of DB, "memory", which loses
+	// them on restart, or "file", which writes them to File as JSON, or
+	// YAML when it ends in .yaml.
+	Store struct {
+		Driver string
+		File   string
 	}
 
+	// Server sets how long the server keeps serving after it starts
+	// reporting not ready on shutdown. GRPCPort serves the gRPC API next to
+	// the HTTP one.
 	Server struct {
-		Port string
+		Port          string
+		GRPCPort      string
+		ShutdownDelay time.Duration
 	}
 
 	Credentials struct {
 		Username string
 		Password string
 	}
+
+	// Auth selects how admin users authenticate: "basic" against the
+	// admin users in the database, or "jwt" with bearer tokens.
+	Auth struct {
+		Mode      string
+		JWKSURL   string
+		KeyFile   string
+		Issuer    string
+		Audience  string
+		RoleClaim string
+		RoleMap   map[string]string
+	}
+
+	// APIKeys sets whether the public calculation endpoints can only be
+	// called with an API key.
+	APIKeys struct {
+		Required bool
+	}
+
+	// Tracing selects where spans are exported: "otlp", "stdout", "file"
+	// or "none".
+	Tracing struct {
+		Exporter    string
+		File        string
+		ServiceName string
+	}
+
+	// Log sets the lowest level that is logged: "debug", "info", "warn" or
+	// "error".
+	Log struct {
+		Level string
+	}
+
+	// Approval sets whether admin deduction changes need to be approved by
+	// a second admin before they apply.
+	Approval struct {
+		Required bool
+	}
+
+	// Deprecation sets when a version of the API was deprecated and when
+	// it stops being served, as announced to the clients still calling it.
+	Deprecation struct {
+		Date   time.Time
+		Sunset time.Time
+	}
 )
 
+const (
+	AuthBasic = "basic"
+	AuthJWT   = "jwt"
+
+	StorePostgres = "postgres"
+	StoreSQLite   = "sqlite"
+	StoreMemory   = "memory"
+	StoreFile     = "file"
+)
+
+// roleMap parses "claim:role" pairs separated by commas.
+func roleMap(s string) map[string]string {
+	m := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		claim, role, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if ok {
+			m[claim] = role
+		}
+	}
+	return m
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func getInt(key string, fallback int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+func getDate(key string, fallback time.Time) time.Time {
+	v, err := time.Parse(time.DateOnly, os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return v
+}
+
+// dbDriver returns the database driver of the store driver, and
+// postgres for the stores that don't use one.
+func dbDriver(store string) string {
+	if store == StoreSQLite {
+		return StoreSQLite
+	}
+	return StorePostgres
+}
+
 func New() *Config {
+	store := getEnv("STORE_DRIVER", StorePostgres)
 	return &Config{
 		DB: DB{
-			Url: os.Getenv("DATABASE_URL"),
+			Driver:          dbDriver(store),
+			Url:             os.Getenv("DATABASE_URL"),
+			AutoMigrate:     os.Getenv("DB_AUTO_MIGRATE") != "false",
+			ConnectTimeout:  getDuration("DB_CONNECT_TIMEOUT", time.Minute),
+			QueryTimeout:    getDuration("DB_QUERY_TIMEOUT", 5*time.Second),
+			MaxOpenConns:    getInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getInt("DB_MAX_IDLE_CONNS", 25),
+			ConnMaxLifetime: getDuration("DB_CONN_MAX_LIFETIME", 5*time.Minute),
+		},
+		Store: Store{
+			Driver: store,
+			File:   getEnv("STORE_FILE", "ktax.json"),
 		},
 		Server: Server{
-			Port: os.Getenv("PORT"),
+			Port:          os.Getenv("PORT"),
+			GRPCPort:      getEnv("GRPC_PORT", "50051"),
+			ShutdownDelay: getDuration("SHUTDOWN_DELAY", 5*time.Second),
 		},
 		Credentials: Credentials{
 			Username: os.Getenv("ADMIN_USERNAME"),
 			Password: os.Getenv("ADMIN_PASSWORD"),
 		},
+		Auth: Auth{
+			Mode:      getEnv("AUTH_MODE", AuthBasic),
+			JWKSURL:   os.Getenv("JWT_JWKS_URL"),
+			KeyFile:   os.Getenv("JWT_KEY_FILE"),
+			Issuer:    os.Getenv("JWT_ISSUER"),
+			Audience:  os.Getenv("JWT_AUDIENCE"),
+			RoleClaim: getEnv("JWT_ROLE_CLAIM", "roles"),
+			RoleMap:   roleMap(os.Getenv("JWT_ROLE_MAP")),
+		},
+		APIKeys: APIKeys{
+			Required: os.Getenv("API_KEY_REQUIRED") != "false",
+		},
+		Approval: Approval{
+			Required: os.Getenv("DEDUCTION_APPROVAL") != "false",
+		},
+		V1: Deprecation{
+			Date:   getDate("V1_DEPRECATION_DATE", time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)),
+			Sunset: getDate("V1_SUNSET_DATE", time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		Log: Log{
+			Level: getEnv("LOG_LEVEL", "info"),
+		},
+		Tracing: Tracing{
+			Exporter:    getEnv("TRACING_EXPORTER", "none"),
+			File:        getEnv("TRACING_FILE", "traces.json"),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "k-taxes"),
+		},
 	}
 }
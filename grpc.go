@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	"github.com/Gitong23/assessment-tax/taxpb"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// adminMethods are the gRPC methods only admin users can call, with the
+// role they require, like the routes under /admin.
+var adminMethods = map[string]string{
+	taxpb.TaxService_GetDeductions_FullMethodName:   auth.RoleViewer,
+	taxpb.TaxService_UpdateDeduction_FullMethodName: auth.RoleEditor,
+}
+
+// newGRPCServer serves tax over gRPC with the health and reflection
+// services. Calculations are rate limited by API key and admin methods are
+// authenticated by authn.
+func newGRPCServer(tax taxpb.TaxServiceServer, limiter *apikey.Limiter, authn auth.Authenticator, health *grpchealth.Server) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			limiter.UnaryInterceptor(taxpb.TaxService_CalculateTax_FullMethodName),
+			auth.UnaryInterceptor(authn, adminMethods),
+		),
+		grpc.ChainStreamInterceptor(
+			limiter.StreamInterceptor(taxpb.TaxService_CalculateBatch_FullMethodName),
+			auth.StreamInterceptor(authn, adminMethods),
+		),
+	)
+	taxpb.RegisterTaxServiceServer(s, tax)
+	healthpb.RegisterHealthServer(s, health)
+	reflection.Register(s)
+	return s
+}
+
+// stopGRPC waits for the calls in progress to finish until ctx is done, and
+// then cancels them.
+func stopGRPC(ctx context.Context, s *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
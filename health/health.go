@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting down"
+)
+
+// checkTimeout bounds how long the readiness checks may take together.
+const checkTimeout = 2 * time.Second
+
+type (
+	// Check reports whether a dependency the service needs to handle
+	// requests is usable.
+	Check struct {
+		Name string
+		Run  func(ctx context.Context) error
+	}
+
+	Handler struct {
+		checks       []Check
+		shuttingDown atomic.Bool
+	}
+
+	Status struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks,omitempty"`
+	}
+)
+
+func NewHandler(checks ...Check) *Handler {
+	return &Handler{checks: checks}
+}
+
+// Shutdown makes the service report not ready so that it stops getting
+// traffic while it drains.
+func (h *Handler) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Live reports that the process is up, whatever the state of its
+// dependencies.
+func (h *Handler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, Status{Status: StatusOK})
+}
+
+// Ready reports whether every check passes.
+func (h *Handler) Ready(c echo.Context) error {
+	if h.shuttingDown.Load() {
+		return c.JSON(http.StatusServiceUnavailable, Status{Status: StatusShuttingDown})
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), checkTimeout)
+	defer cancel()
+
+	res := Status{Status: StatusOK, Checks: map[string]string{}}
+	for _, check := range h.checks {
+		err := check.Run(ctx)
+		if err != nil {
+			res.Status = StatusUnavailable
+			res.Checks[check.Name] = err.Error()
+			continue
+		}
+		res.Checks[check.Name] = StatusOK
+	}
+
+	if res.Status != StatusOK {
+		return c.JSON(http.StatusServiceUnavailable, res)
+	}
+	return c.JSON(http.StatusOK, res)
+}
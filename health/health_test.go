@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func ok(ctx context.Context) error {
+	return nil
+}
+
+func down(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   []Check
+		shutdown bool
+		wantHttp int
+		want     Status
+	}{
+		{
+			name:     "All checks pass",
+			checks:   []Check{{Name: "database", Run: ok}, {Name: "migrations", Run: ok}},
+			wantHttp: http.StatusOK,
+			want:     Status{Status: StatusOK, Checks: map[string]string{"database": StatusOK, "migrations": StatusOK}},
+		},
+		{
+			name:     "Database down",
+			checks:   []Check{{Name: "database", Run: down}, {Name: "migrations", Run: ok}},
+			wantHttp: http.StatusServiceUnavailable,
+			want:     Status{Status: StatusUnavailable, Checks: map[string]string{"database": "connection refused", "migrations": StatusOK}},
+		},
+		{
+			name:     "Shutting down",
+			checks:   []Check{{Name: "database", Run: ok}},
+			shutdown: true,
+			wantHttp: http.StatusServiceUnavailable,
+			want:     Status{Status: StatusShuttingDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(tt.checks...)
+			if tt.shutdown {
+				h.Shutdown()
+			}
+
+			e := echo.New()
+			e.GET("/readyz", h.Ready)
+			e.GET("/healthz", h.Live)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+
+			var got Status
+			err := json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Errorf("error unmarshalling json: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected liveness status code %d but got %d", http.StatusOK, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrStoreUnavailable is wrapped by store errors that mean the database
+// couldn't be reached or didn't answer in time.
+var ErrStoreUnavailable = errors.New("store unavailable")
+
+// StoreError returns the status code and message to respond with when a
+// store call fails.
+func StoreError(err error) (int, string) {
+	if errors.Is(err, ErrStoreUnavailable) {
+		return http.StatusServiceUnavailable, "Service Unavailable"
+	}
+	return http.StatusInternalServerError, "Internal Server Error"
+}
@@ -7,12 +7,19 @@ import (
 	"regexp"
 )
 
+var thousands = regexp.MustCompile("(\\d+)(\\d{3})")
+
 func Comma(num float64) string {
+	return Group(num, ",")
+}
+
+// Group rounds num to a whole number and separates its thousands with
+// separator.
+func Group(num float64, separator string) string {
 	str := fmt.Sprintf("%.0f", num)
-	re := regexp.MustCompile("(\\d+)(\\d{3})")
 	for n := ""; n != str; {
 		n = str
-		str = re.ReplaceAllString(str, "$1,$2")
+		str = thousands.ReplaceAllString(str, "${1}"+separator+"${2}")
 	}
 	return str
 }
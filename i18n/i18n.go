@@ -0,0 +1,145 @@
+// Package i18n picks the language of a request and translates the messages
+// and numbers of its response into it.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	Thai    = "th"
+	English = "en"
+
+	// Default is the language of clients that don't ask for one. The app
+	// is Thai-first.
+	Default = Thai
+
+	// QueryParam overrides Accept-Language when a client can't set headers.
+	QueryParam = "lang"
+
+	HeaderContentLanguage = "Content-Language"
+
+	printerKey = "i18nPrinter"
+)
+
+type locale struct {
+	// messages translates the English messages of the code. English itself
+	// has none.
+	messages  map[string]string
+	thousands string
+}
+
+var locales = map[string]locale{
+	Thai:    {messages: th, thousands: ","},
+	English: {messages: map[string]string{}, thousands: ","},
+}
+
+// matcher prefers Default when nothing the client asks for is supported.
+var matcher = language.NewMatcher([]language.Tag{language.Thai, language.English})
+
+// Printer translates into one language.
+type Printer struct {
+	lang string
+	locale
+}
+
+// NewPrinter returns the printer of lang, or of Default when lang isn't
+// supported.
+func NewPrinter(lang string) *Printer {
+	l, ok := locales[lang]
+	if !ok {
+		lang, l = Default, locales[Default]
+	}
+	return &Printer{lang: lang, locale: l}
+}
+
+func (p *Printer) Lang() string {
+	return p.lang
+}
+
+// Sprintf translates format, then formats it with args. format is returned
+// as is when it has no translation.
+func (p *Printer) Sprintf(format string, args ...interface{}) string {
+	if t, ok := p.messages[format]; ok {
+		format = t
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Number formats num as a whole number the way the language writes it.
+func (p *Printer) Number(num float64) string {
+	return helper.Group(num, p.thousands)
+}
+
+// match returns the supported language closest to the ones in accept, an
+// Accept-Language value, or "" when none is close enough.
+func match(accept string) string {
+	tags, _, err := language.ParseAcceptLanguage(accept)
+	if err != nil || len(tags) == 0 {
+		return ""
+	}
+
+	tag, _, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return ""
+	}
+	base, _ := tag.Base()
+	return base.String()
+}
+
+// Negotiate returns the language of r from the lang query parameter, then
+// Accept-Language, then Default.
+func Negotiate(r *http.Request) string {
+	if lang := match(r.URL.Query().Get(QueryParam)); lang != "" {
+		return lang
+	}
+	if lang := match(r.Header.Get("Accept-Language")); lang != "" {
+		return lang
+	}
+	return Default
+}
+
+// Middleware negotiates the language of every request and tells caches the
+// response depends on it.
+func Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		p := NewPrinter(Negotiate(c.Request()))
+		c.Set(printerKey, p)
+
+		h := c.Response().Header()
+		h.Set(HeaderContentLanguage, p.Lang())
+		h.Add(echo.HeaderVary, "Accept-Language")
+		return next(c)
+	}
+}
+
+// For returns the printer of the request, negotiating its language when
+// Middleware didn't run.
+func For(c echo.Context) *Printer {
+	if p, ok := c.Get(printerKey).(*Printer); ok {
+		return p
+	}
+	return NewPrinter(Negotiate(c.Request()))
+}
+
+// ForGRPC returns the printer of a gRPC call, negotiating its language from
+// the accept-language metadata.
+func ForGRPC(ctx context.Context) *Printer {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, accept := range md.Get("accept-language") {
+		if lang := match(accept); lang != "" {
+			return NewPrinter(lang)
+		}
+	}
+	return NewPrinter(Default)
+}
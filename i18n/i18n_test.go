@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		accept string
+		want   string
+	}{
+		{name: "Nothing asked for", url: "/", want: Thai},
+		{name: "English region", url: "/", accept: "en-US,en;q=0.9", want: English},
+		{name: "Thai preferred over English", url: "/", accept: "th-TH,en;q=0.8", want: Thai},
+		{name: "Unsupported language falls back", url: "/", accept: "fr-FR", want: Default},
+		{name: "Supported language further down", url: "/", accept: "fr-FR,en;q=0.5", want: English},
+		{name: "Query parameter overrides header", url: "/?lang=en", accept: "th", want: English},
+		{name: "Unsupported query parameter is ignored", url: "/?lang=xx", accept: "en", want: English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+
+			if got := Negotiate(req); got != tt.want {
+				t.Errorf("expected %s but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	tests := []struct {
+		lang   string
+		format string
+		args   []interface{}
+		want   string
+	}{
+		{lang: Thai, format: "%s and above", args: []interface{}{"2,000,001"}, want: "2,000,001 ขึ้นไป"},
+		{lang: English, format: "%s and above", args: []interface{}{"2,000,001"}, want: "2,000,001 and above"},
+		{lang: Thai, format: "Not translated", want: "Not translated"},
+		{lang: "fr", format: "Invalid WHT value", want: "ภาษีหัก ณ ที่จ่ายไม่ถูกต้อง"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+" "+tt.format, func(t *testing.T) {
+			if got := NewPrinter(tt.lang).Sprintf(tt.format, tt.args...); got != tt.want {
+				t.Errorf("expected %s but got %s", tt.want, got)
+			}
+		})
+	}
+
+	if got := NewPrinter(English).Number(2000001); got != "2,000,001" {
+		t.Errorf("expected 2,000,001 but got %s", got)
+	}
+}
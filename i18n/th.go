@@ -0,0 +1,88 @@
+package i18n
+
+// th translates the messages of the responses into Thai. Keys are the
+// English messages, as written in the code.
+var th = map[string]string{
+	// Statuses
+	"Bad Request":              "คำขอไม่ถูกต้อง",
+	"Unauthorized":             "ยังไม่ได้ยืนยันตัวตน",
+	"Forbidden":                "ไม่มีสิทธิ์เข้าถึง",
+	"Not Found":                "ไม่พบข้อมูลที่ร้องขอ",
+	"Method Not Allowed":       "ไม่รองรับเมธอดนี้",
+	"Conflict":                 "ข้อมูลขัดแย้งกัน",
+	"Request Entity Too Large": "ข้อมูลมีขนาดใหญ่เกินไป",
+	"Too Many Requests":        "มีคำขอมากเกินไป",
+	"Internal Server Error":    "เกิดข้อผิดพลาดภายในระบบ",
+	"Service Unavailable":      "ระบบไม่พร้อมให้บริการชั่วคราว",
+
+	// Validation
+	"Invalid request body": "ข้อมูลคำขอไม่ถูกต้อง",
+	"Is required":          "จำเป็นต้องระบุ",
+	"Must be one of %s":    "ต้องเป็นค่าใดค่าหนึ่งต่อไปนี้: %s",
+	"Must be at least %s":  "ต้องไม่น้อยกว่า %s",
+	"Must be at most %s":   "ต้องไม่มากกว่า %s",
+	"Must be more than %s": "ต้องมากกว่า %s",
+	"Must be a %s":         "ต้องเป็นชนิด %s",
+	"Invalid value":        "ค่าไม่ถูกต้อง",
+
+	// Tax
+	"%s and above":                                        "%s ขึ้นไป",
+	"Invalid form data":                                   "ข้อมูลฟอร์มไม่ถูกต้อง",
+	"Only CSV files are allowed":                          "รองรับเฉพาะไฟล์ CSV เท่านั้น",
+	"Invalid CSV file":                                    "ไฟล์ CSV ไม่ถูกต้อง",
+	"Invalid CSV file content":                            "เนื้อหาไฟล์ CSV ไม่ถูกต้อง",
+	"Invalid CSV header":                                  "หัวตารางไฟล์ CSV ไม่ถูกต้อง",
+	"Invalid TotalIncome value":                           "รายได้รวมไม่ถูกต้อง",
+	"Invalid WHT value":                                   "ภาษีหัก ณ ที่จ่ายไม่ถูกต้อง",
+	"Invalid Donation value":                              "เงินบริจาคไม่ถูกต้อง",
+	"Invalid K-receipt value":                             "ค่า K-receipt ไม่ถูกต้อง",
+	"Invalid date value":                                  "วันที่ไม่ถูกต้อง",
+	"Invalid currency value":                              "สกุลเงินไม่ถูกต้อง",
+	"Invalid rate value":                                  "อัตราแลกเปลี่ยนไม่ถูกต้อง",
+	"Invalid currency code":                               "รหัสสกุลเงินไม่ถูกต้อง",
+	"Invalid received date":                               "วันที่ได้รับเงินไม่ถูกต้อง",
+	"Invalid income amount":                               "จำนวนเงินได้ไม่ถูกต้อง",
+	"No exchange rate for %s on %s":                       "ไม่พบอัตราแลกเปลี่ยน %s ของวันที่ %s",
+	"Invalid %s amount":                                   "จำนวนเงิน %s ไม่ถูกต้อง",
+	"Invalid dividend rate":                               "อัตราภาษีเงินปันผลไม่ถูกต้อง",
+	"Invalid foreign tax paid":                            "ภาษีที่ชำระในต่างประเทศไม่ถูกต้อง",
+	"Invalid credit type":                                 "ประเภทเครดิตภาษีไม่ถูกต้อง",
+	"Invalid period value":                                "งวดการยื่นแบบไม่ถูกต้อง",
+	"Invalid tax year":                                    "ปีภาษีไม่ถูกต้อง",
+	"Invalid half-year tax value":                         "ภาษีครึ่งปีไม่ถูกต้อง",
+	"Invalid penalty value":                               "ประเภทเบี้ยปรับไม่ถูกต้อง",
+	"Invalid due date":                                    "วันครบกำหนดไม่ถูกต้อง",
+	"Invalid filing date":                                 "วันที่ยื่นแบบไม่ถูกต้อง",
+	"Invalid deduction type":                              "ประเภทค่าลดหย่อนไม่ถูกต้อง",
+	"Invalid personal deduction amount":                   "จำนวนค่าลดหย่อนส่วนตัวไม่ถูกต้อง",
+	"Invalid K-receipt deduction amount":                  "จำนวนค่าลดหย่อน K-receipt ไม่ถูกต้อง",
+	"Foreign income can't be more than total income":      "เงินได้จากต่างประเทศต้องไม่มากกว่ารายได้รวม",
+	"Half-year tax can only be credited in annual period": "นำภาษีครึ่งปีมาเครดิตได้เฉพาะการยื่นแบบทั้งปี",
+	"Spouses must file for the same period":               "คู่สมรสต้องยื่นแบบในงวดเดียวกัน",
+	"Late filing is not supported for household":          "การคำนวณภาษีคู่สมรสไม่รองรับการยื่นแบบล่าช้า",
+	"CSV row quota exceeded":                              "จำนวนแถว CSV เกินโควตา",
+	"Row quota exceeded":                                  "จำนวนแถวเกินโควตา",
+
+	// Deduction changes
+	"Invalid status":                                    "สถานะไม่ถูกต้อง",
+	"Invalid change id":                                 "รหัสคำขอเปลี่ยนแปลงไม่ถูกต้อง",
+	"Invalid comment":                                   "ความคิดเห็นไม่ถูกต้อง",
+	"Deduction change not found":                        "ไม่พบคำขอเปลี่ยนแปลงค่าลดหย่อน",
+	"Deduction change is already %s":                    "คำขอเปลี่ยนแปลงค่าลดหย่อนถูก %s แล้ว",
+	"Deduction change is no longer pending":             "คำขอเปลี่ยนแปลงค่าลดหย่อนไม่ได้รอการพิจารณาแล้ว",
+	"Deduction change must be decided by another admin": "คำขอเปลี่ยนแปลงค่าลดหย่อนต้องพิจารณาโดยผู้ดูแลระบบคนอื่น",
+
+	// Admin users and API keys
+	"Invalid username":                        "ชื่อผู้ใช้ไม่ถูกต้อง",
+	"Password must be at least %d characters": "รหัสผ่านต้องมีอย่างน้อย %d ตัวอักษร",
+	"Invalid role":                            "บทบาทไม่ถูกต้อง",
+	"Username already exists":                 "ชื่อผู้ใช้นี้มีอยู่แล้ว",
+	"Can't delete yourself":                   "ไม่สามารถลบบัญชีของตัวเองได้",
+	"Admin user not found":                    "ไม่พบผู้ดูแลระบบ",
+	"Missing API key":                         "ไม่พบ API key",
+	"Invalid API key":                         "API key ไม่ถูกต้อง",
+	"Invalid API key id":                      "รหัส API key ไม่ถูกต้อง",
+	"Rate limit exceeded":                     "จำนวนคำขอเกินขีดจำกัด",
+	"Invalid name":                            "ชื่อไม่ถูกต้อง",
+	"Invalid quota value":                     "โควตาไม่ถูกต้อง",
+}
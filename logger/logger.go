@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the attribute keys, lower-cased without separators,
+// whose values never reach the logs.
+var sensitiveKeys = map[string]bool{
+	"income":        true,
+	"totalincome":   true,
+	"amount":        true,
+	"wht":           true,
+	"nationalid":    true,
+	"password":      true,
+	"passwordhash":  true,
+	"token":         true,
+	"apikey":        true,
+	"authorization": true,
+	"databaseurl":   true,
+	"dsn":           true,
+}
+
+type ctxKey struct{}
+
+// ParseLevel returns the level named by s, or info when s isn't one.
+func ParseLevel(s string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+func normalize(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "", ".", "").Replace(key)
+}
+
+// redact hides the values of sensitive keys and the passwords of URLs.
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if sensitiveKeys[normalize(a.Key)] {
+		return slog.String(a.Key, redacted)
+	}
+
+	if a.Value.Kind() == slog.KindString {
+		s := a.Value.String()
+		if u, err := url.Parse(s); err == nil && u.User != nil {
+			return slog.String(a.Key, u.Redacted())
+		}
+	}
+	return a
+}
+
+// New returns a JSON logger writing to w that logs at level and above and
+// redacts sensitive values.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}))
+}
+
+// WithContext returns ctx carrying l.
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the logger in ctx, or the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// RequestID returns the ID of the request, set by echo's RequestID
+// middleware from the incoming X-Request-ID header or generated.
+func RequestID(c echo.Context) string {
+	return c.Response().Header().Get(echo.HeaderXRequestID)
+}
+
+// Middleware gives every request a logger carrying its request and trace
+// IDs and logs the request once it's handled. It runs after echo's
+// RequestID middleware.
+func Middleware(l *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			rl := l.With("requestId", RequestID(c))
+			if sc := trace.SpanContextFromContext(req.Context()); sc.IsValid() {
+				rl = rl.With("traceId", sc.TraceID().String())
+			}
+			c.SetRequest(req.WithContext(WithContext(req.Context(), rl)))
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			status := c.Response().Status
+			level := slog.LevelInfo
+			if status >= 500 {
+				level = slog.LevelError
+			}
+			rl.Log(req.Context(), level, "request",
+				"method", req.Method,
+				"route", c.Path(),
+				"uri", req.RequestURI,
+				"status", status,
+				"latency", time.Since(start).String(),
+				"remoteIp", c.RealIP(),
+			)
+			return nil
+		}
+	}
+}
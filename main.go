@@ -3,66 +3,175 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	cfg "github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/health"
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/metrics"
 	"github.com/Gitong23/assessment-tax/postgres"
+	"github.com/Gitong23/assessment-tax/problem"
 	"github.com/Gitong23/assessment-tax/tax"
+	"github.com/Gitong23/assessment-tax/tracing"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	grpchealth "google.golang.org/grpc/health"
 )
 
 func main() {
 
-	config := config.New()
-	p, err := postgres.New()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := migrate(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	config := cfg.New()
+	log := logger.New(os.Stdout, logger.ParseLevel(config.Log.Level))
+	slog.SetDefault(log)
+
+	s, checks, err := openStore(config)
+	if err != nil {
+		panic(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    config.Tracing.Exporter,
+		File:        config.Tracing.File,
+		ServiceName: config.Tracing.ServiceName,
+	})
 	if err != nil {
 		panic(err)
 	}
 
 	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
 	e.Validator = tax.NewValidator()
-	e.Use(middleware.Logger())
+	e.HTTPErrorHandler = problem.ErrorHandler
+	e.Use(middleware.RequestID())
+	e.Use(metrics.Middleware)
+	e.Use(tracing.Middleware)
+	e.Use(logger.Middleware(log))
+	e.Use(i18n.Middleware)
 
-	e.GET("/", func(c echo.Context) error {
-		return c.String(http.StatusOK, "Hello, Go Bootcamp!")
-	})
+	handler := tax.NewHandler(s)
+
+	// Other instances change the allowances of the Postgres database. The
+	// other stores, a SQLite file included, are only changed by this one,
+	// which invalidates the cache itself.
+	listening := func() bool { return true }
+	if p, ok := s.(*postgres.Postgres); ok {
+		listener, err := p.Listen(postgres.AllowancesChanged, handler.Deductors().Invalidate)
+		if err != nil {
+			panic(err)
+		}
+		defer listener.Close()
+		listening = listener.Connected
+	}
+
+	probes := health.NewHandler(append(checks,
+		health.Check{Name: "deductions", Run: func(ctx context.Context) error {
+			if !listening() {
+				return fmt.Errorf("not listening for allowance changes")
+			}
+			_, err := handler.Deductors().Deductor(ctx)
+			return err
+		}},
+	)...)
 
-	handler := tax.NewHandler(p)
-	e.POST("/tax/calculations", handler.Tax)
-	e.POST("/tax/calculations/upload-csv", handler.UploadCsv)
+	a := api{
+		tax:     handler,
+		users:   auth.NewHandler(s),
+		keys:    apikey.NewHandler(s),
+		limiter: apikey.NewLimiter(s, config.APIKeys.Required),
+		probes:  probes,
+		spec:    newSpec(),
+		v1:      config.V1,
+	}
+	if config.Approval.Required {
+		a.approvals = tax.NewApprovalHandler(s, s, handler.Deductors())
+	}
 
-	g := e.Group("/admin")
-	g.Use(middleware.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
-		if username == config.Credentials.Username && password == config.Credentials.Password {
-			return true, nil
+	var authn auth.Authenticator
+	switch config.Auth.Mode {
+	case cfg.AuthJWT:
+		verifier, err := auth.NewVerifier(auth.JWTConfig{
+			JWKSURL:   config.Auth.JWKSURL,
+			KeyFile:   config.Auth.KeyFile,
+			Issuer:    config.Auth.Issuer,
+			Audience:  config.Auth.Audience,
+			RoleClaim: config.Auth.RoleClaim,
+			RoleMap:   config.Auth.RoleMap,
+		})
+		if err != nil {
+			panic(err)
+		}
+		a.admin = auth.JWT(verifier)
+		authn = auth.Bearer(verifier)
+	case cfg.AuthBasic:
+		err = auth.Bootstrap(context.Background(), s, config.Credentials.Username, config.Credentials.Password)
+		if err != nil {
+			panic(err)
 		}
-		return false, c.JSON(http.StatusUnauthorized, tax.Err{Message: "Unauthorized"})
-	}))
+		a.admin = auth.BasicAuth(s)
+		authn = auth.Basic(s)
+	default:
+		panic(fmt.Sprintf("unknown AUTH_MODE %q", config.Auth.Mode))
+	}
+	routes(e, a)
 
-	g.POST("/deductions/personal", handler.UpdateInitPersonalDeduct)
-	g.POST("/deductions/k-receipt", handler.UpdateMaxKreceiptDeduct)
+	grpcHealth := grpchealth.NewServer()
+	grpcServer := newGRPCServer(tax.NewGRPCServer(handler, a.approvals), a.limiter, authn, grpcHealth)
 
 	// Graceful shutdown
 	go func() {
 		port := fmt.Sprintf(":%s", config.Server.Port)
+		log.Info("starting the server", "port", config.Server.Port)
 		if err := e.Start(port); err != nil && err != http.ErrServerClosed {
-			e.Logger.Info("shutting down the server")
+			log.Error("server stopped", "error", err)
+		}
+	}()
+
+	go func() {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", config.Server.GRPCPort))
+		if err != nil {
+			log.Error("listening for gRPC", "error", err)
+			return
+		}
+		log.Info("starting the gRPC server", "port", config.Server.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Error("gRPC server stopped", "error", err)
 		}
 	}()
 
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
 	<-shutdown
-	fmt.Println("shutting down the server")
+	log.Info("shutting down the server")
+	probes.Shutdown()
+	grpcHealth.Shutdown()
+	time.Sleep(config.Server.ShutdownDelay)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	stopGRPC(ctx, grpcServer)
 	if err := e.Shutdown(ctx); err != nil {
-		e.Logger.Fatal(err)
+		log.Error("shutting down the server", "error", err)
+		os.Exit(1)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("flushing traces", "error", err)
 	}
 
 }
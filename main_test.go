@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	cfg "github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/openapi"
+	"github.com/Gitong23/assessment-tax/tax"
+	"github.com/Gitong23/assessment-tax/taxpb"
+	"github.com/labstack/echo/v4"
+	grpchealth "google.golang.org/grpc/health"
+)
+
+func TestSpecRoutes(t *testing.T) {
+	tests := []struct {
+		name      string
+		approvals *tax.ApprovalHandler
+	}{
+		{name: "Deduction changes applied directly"},
+		{name: "Deduction changes approved", approvals: &tax.ApprovalHandler{}},
+	}
+
+	spec := newSpec()
+	documented := map[string]bool{}
+	for _, r := range spec.Routes() {
+		documented[r] = true
+	}
+
+	served := map[string]bool{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			routes(e, api{
+				approvals: tt.approvals,
+				admin:     func(next echo.HandlerFunc) echo.HandlerFunc { return next },
+				spec:      spec,
+			})
+
+			for _, r := range e.Routes() {
+				if r.Method == echo.RouteNotFound {
+					continue
+				}
+				if !documentedPath(r.Path) {
+					continue
+				}
+				route := r.Method + " " + r.Path
+				served[route] = true
+				if !documented[route] {
+					t.Errorf("expected %s to be in the OpenAPI document", route)
+				}
+			}
+		})
+	}
+
+	var stale []string
+	for r := range documented {
+		if !served[r] {
+			stale = append(stale, r)
+		}
+	}
+	sort.Strings(stale)
+	if len(stale) > 0 {
+		t.Errorf("expected the documented routes %v to be served", stale)
+	}
+}
+
+// documentedPath reports whether path is one of the calculation and admin
+// routes the spec documents.
+func documentedPath(path string) bool {
+	for _, prefix := range []string{"/tax/", "/v1/", "/v2/", "/admin/"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDeprecated(t *testing.T) {
+	d := cfg.Deprecation{
+		Date:   time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+	}
+	e := echo.New()
+	e.POST("/v1/tax/calculations", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, deprecated(d, "/v2/tax/calculations"))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/tax/calculations", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	want := map[string]string{
+		"Deprecation": "@1792368000",
+		"Sunset":      "Thu, 01 Apr 2027 00:00:00 GMT",
+		"Link":        `</v2/tax/calculations>; rel="successor-version"`,
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("expected %s header %s but got %s", header, value, got)
+		}
+	}
+}
+
+func TestSpecJSON(t *testing.T) {
+	e := echo.New()
+	spec := newSpec()
+	e.GET("/openapi.json", spec.JSON)
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status code %d but got %d", http.StatusOK, rec.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("error unmarshalling json: %v", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("expected openapi %s but got %s", openapi.Version, doc.OpenAPI)
+	}
+
+	schema := doc.Components.Schemas["TaxRequest"]
+	if schema == nil {
+		t.Fatalf("expected a TaxRequest schema")
+	}
+	if min := schema.Properties["totalIncome"].Minimum; min == nil || *min != 0 {
+		t.Errorf("expected totalIncome to have a minimum of 0 but got %v", min)
+	}
+	if want := []string{"allowanceType"}; !reflect.DeepEqual(doc.Components.Schemas["AllowanceReq"].Required, want) {
+		t.Errorf("expected AllowanceReq to require %v but got %v", want, doc.Components.Schemas["AllowanceReq"].Required)
+	}
+	for _, name := range []string{"TaxResponse", "TaxUploadResponse", "Problem"} {
+		if doc.Components.Schemas[name] == nil {
+			t.Errorf("expected a %s schema", name)
+		}
+	}
+
+	op := doc.Paths["/tax/calculations"]["post"]
+	if op == nil {
+		t.Fatalf("expected POST /tax/calculations to be documented")
+	}
+	if got := op.RequestBody.Content["application/json"].Schema.Ref; got != "#/components/schemas/TaxRequest" {
+		t.Errorf("expected the request body to refer to TaxRequest but got %s", got)
+	}
+	if !op.Deprecated {
+		t.Errorf("expected POST /tax/calculations to be deprecated")
+	}
+
+	v2 := doc.Components.Schemas["TaxResponseV2"]
+	if v2 == nil {
+		t.Fatalf("expected a TaxResponseV2 schema")
+	}
+	if got := v2.Properties["taxRefund"]; got.Type != "string" || got.Format != "decimal" {
+		t.Errorf("expected taxRefund to be a decimal string but got %s %s", got.Type, got.Format)
+	}
+	if doc.Components.Schemas["TaxRequestV2"].Properties["taxYear"].Description == "" {
+		t.Errorf("expected taxYear to be described")
+	}
+}
+
+func TestGRPCServices(t *testing.T) {
+	s := newGRPCServer(&taxpb.UnimplementedTaxServiceServer{}, apikey.NewLimiter(nil, false), nil, grpchealth.NewServer())
+
+	for _, name := range []string{"ktax.v1.TaxService", "grpc.health.v1.Health", "grpc.reflection.v1.ServerReflection"} {
+		if _, ok := s.GetServiceInfo()[name]; !ok {
+			t.Errorf("expected the %s service to be registered", name)
+		}
+	}
+}
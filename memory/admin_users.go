@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/auth"
+)
+
+func (u user) user() auth.User {
+	return auth.User{ID: u.ID, Username: u.Username, Role: u.Role, PasswordHash: u.PasswordHash, CreatedAt: u.CreatedAt}
+}
+
+func (s *Store) AdminUser(ctx context.Context, username string) (*auth.User, error) {
+	var found *auth.User
+	s.view(func(st *state) {
+		for _, u := range st.Users {
+			if u.Username == username {
+				au := u.user()
+				found = &au
+				return
+			}
+		}
+	})
+	return found, nil
+}
+
+func (s *Store) AdminUsers(ctx context.Context) ([]auth.User, error) {
+	users := []auth.User{}
+	s.view(func(st *state) {
+		for _, u := range st.Users {
+			users = append(users, u.user())
+		}
+	})
+	return users, nil
+}
+
+// CreateAdminUser fails when the username is taken, like the unique
+// username of the database.
+func (s *Store) CreateAdminUser(ctx context.Context, u auth.User) (*auth.User, error) {
+	err := s.update(func(st *state) error {
+		for _, e := range st.Users {
+			if e.Username == u.Username {
+				return fmt.Errorf("admin user %s already exists", u.Username)
+			}
+		}
+
+		u.ID = st.next(seqUsers)
+		u.CreatedAt = timestamp()
+		st.Users = append(st.Users, user{ID: u.ID, Username: u.Username, Role: u.Role, PasswordHash: u.PasswordHash, CreatedAt: u.CreatedAt})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (s *Store) DeleteAdminUser(ctx context.Context, username string) error {
+	return s.update(func(st *state) error {
+		for i, u := range st.Users {
+			if u.Username == username {
+				st.Users = append(st.Users[:i:i], st.Users[i+1:]...)
+				return nil
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+// deductionFields is the allowance field each deduction change sets.
+var deductionFields = map[string]func(a *tax.Allowances, amount float64){
+	"personal":  func(a *tax.Allowances, amount float64) { a.InitAmount = amount },
+	"k-receipt": func(a *tax.Allowances, amount float64) { a.MaxAmount = amount },
+}
+
+func (s *Store) allowance(t string) *tax.Allowances {
+	var a tax.Allowances
+	s.view(func(st *state) {
+		a = st.Allowances[allowanceIndex(st.Allowances, t)]
+	})
+	return &a
+}
+
+// setAllowance sets the field of a deduction change on the allowance of
+// type t.
+func (s *Store) setAllowance(t string, amount float64) (*tax.Allowances, error) {
+	err := s.update(func(st *state) error {
+		deductionFields[t](&st.Allowances[allowanceIndex(st.Allowances, t)], amount)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.allowance(t), nil
+}
+
+func (s *Store) PersonalAllowance(ctx context.Context) (*tax.Allowances, error) {
+	return s.allowance("personal"), nil
+}
+
+func (s *Store) DonationAllowance(ctx context.Context) (*tax.Allowances, error) {
+	return s.allowance("donation"), nil
+}
+
+func (s *Store) KreceiptAllowance(ctx context.Context) (*tax.Allowances, error) {
+	return s.allowance("k-receipt"), nil
+}
+
+func (s *Store) UpdateInitPersonalAllowance(ctx context.Context, amount float64) (*tax.Allowances, error) {
+	return s.setAllowance("personal", amount)
+}
+
+func (s *Store) UpdateMaxAmountKreceipt(ctx context.Context, amount float64) (*tax.Allowances, error) {
+	return s.setAllowance("k-receipt", amount)
+}
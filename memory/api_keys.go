@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+)
+
+func (k key) key() apikey.Key {
+	ak := apikey.Key{
+		ID:                k.ID,
+		Name:              k.Name,
+		Prefix:            k.Prefix,
+		Hash:              k.Hash,
+		RequestsPerMinute: k.RequestsPerMinute,
+		RowsPerDay:        k.RowsPerDay,
+		CreatedAt:         k.CreatedAt,
+	}
+	if k.RevokedAt != nil {
+		revokedAt := *k.RevokedAt
+		ak.RevokedAt = &revokedAt
+	}
+	return ak
+}
+
+func (s *Store) APIKey(ctx context.Context, hash string) (*apikey.Key, error) {
+	var found *apikey.Key
+	s.view(func(st *state) {
+		for _, k := range st.Keys {
+			if k.Hash == hash {
+				ak := k.key()
+				found = &ak
+				return
+			}
+		}
+	})
+	return found, nil
+}
+
+func (s *Store) APIKeys(ctx context.Context) ([]apikey.Key, error) {
+	keys := []apikey.Key{}
+	s.view(func(st *state) {
+		for _, k := range st.Keys {
+			keys = append(keys, k.key())
+		}
+	})
+	return keys, nil
+}
+
+// CreateAPIKey fails when the hash is taken, like the unique hash of the
+// database.
+func (s *Store) CreateAPIKey(ctx context.Context, k apikey.Key) (*apikey.Key, error) {
+	var created apikey.Key
+	err := s.update(func(st *state) error {
+		for _, e := range st.Keys {
+			if e.Hash == k.Hash {
+				return fmt.Errorf("API key %s already exists", k.Prefix)
+			}
+		}
+
+		stored := key{
+			ID:                st.next(seqKeys),
+			Name:              k.Name,
+			Prefix:            k.Prefix,
+			Hash:              k.Hash,
+			RequestsPerMinute: k.RequestsPerMinute,
+			RowsPerDay:        k.RowsPerDay,
+			CreatedAt:         timestamp(),
+		}
+		st.Keys = append(st.Keys, stored)
+		created = stored.key()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// RevokeAPIKey keeps the time a key was first revoked.
+func (s *Store) RevokeAPIKey(ctx context.Context, id int) error {
+	return s.update(func(st *state) error {
+		for i, k := range st.Keys {
+			if k.ID == id && k.RevokedAt == nil {
+				revokedAt := timestamp()
+				st.Keys[i].RevokedAt = &revokedAt
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) RecordUsage(ctx context.Context, id int, date string, requests int, rows int) (*apikey.Usage, error) {
+	var total apikey.Usage
+	err := s.update(func(st *state) error {
+		for i, u := range st.Usage {
+			if u.KeyID == id && u.Date == date {
+				st.Usage[i].Requests += requests
+				st.Usage[i].Rows += rows
+				total = apikey.Usage{Date: date, Requests: st.Usage[i].Requests, Rows: st.Usage[i].Rows}
+				return nil
+			}
+		}
+
+		st.Usage = append(st.Usage, usage{KeyID: id, Date: date, Requests: requests, Rows: rows})
+		total = apikey.Usage{Date: date, Requests: requests, Rows: rows}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &total, nil
+}
+
+// Usage returns the daily usage of the key of id, latest first.
+func (s *Store) Usage(ctx context.Context, id int) ([]apikey.Usage, error) {
+	usage := []apikey.Usage{}
+	s.view(func(st *state) {
+		for _, u := range st.Usage {
+			if u.KeyID == id {
+				usage = append(usage, apikey.Usage{Date: u.Date, Requests: u.Requests, Rows: u.Rows})
+			}
+		}
+	})
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Date > usage[j].Date })
+	return usage, nil
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func changeIndex(st *state, id int) int {
+	for i, c := range st.Changes {
+		if c.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// copyChange copies c with its comments, or without them when withComments
+// is false, like the lists of the database.
+func copyChange(c tax.DeductionChange, withComments bool) *tax.DeductionChange {
+	c.Comments = append([]tax.ChangeComment(nil), c.Comments...)
+	if !withComments {
+		c.Comments = nil
+	}
+	return &c
+}
+
+func (s *Store) CreateDeductionChange(ctx context.Context, change tax.DeductionChange) (*tax.DeductionChange, error) {
+	var created *tax.DeductionChange
+	err := s.update(func(st *state) error {
+		c := tax.DeductionChange{
+			ID:          st.next(seqChanges),
+			Type:        change.Type,
+			Amount:      change.Amount,
+			Status:      change.Status,
+			SubmittedBy: change.SubmittedBy,
+			CreatedAt:   timestamp(),
+		}
+		st.Changes = append(st.Changes, c)
+		created = copyChange(c, false)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// DeductionChanges returns the changes of status, or all of them when it's
+// "", newest first.
+func (s *Store) DeductionChanges(ctx context.Context, status string) ([]tax.DeductionChange, error) {
+	changes := []tax.DeductionChange{}
+	s.view(func(st *state) {
+		for i := len(st.Changes) - 1; i >= 0; i-- {
+			if status == "" || st.Changes[i].Status == status {
+				changes = append(changes, *copyChange(st.Changes[i], false))
+			}
+		}
+	})
+	return changes, nil
+}
+
+func (s *Store) DeductionChange(ctx context.Context, id int) (*tax.DeductionChange, error) {
+	var change *tax.DeductionChange
+	s.view(func(st *state) {
+		if idx := changeIndex(st, id); idx >= 0 {
+			change = copyChange(st.Changes[idx], true)
+		}
+	})
+	return change, nil
+}
+
+// DecideDeductionChange returns nil when there's no pending change of id.
+func (s *Store) DecideDeductionChange(ctx context.Context, id int, status string, by string) (*tax.DeductionChange, error) {
+	var decided *tax.DeductionChange
+	err := s.update(func(st *state) error {
+		idx := changeIndex(st, id)
+		if idx < 0 || st.Changes[idx].Status != tax.ChangePending {
+			return nil
+		}
+
+		c := &st.Changes[idx]
+		set, ok := deductionFields[c.Type]
+		if status == tax.ChangeApproved && !ok {
+			return fmt.Errorf("unknown deduction type %s", c.Type)
+		}
+
+		c.Status = status
+		c.DecidedBy = by
+		c.DecidedAt = timestamp()
+		if status == tax.ChangeApproved {
+			set(&st.Allowances[allowanceIndex(st.Allowances, c.Type)], c.Amount)
+		}
+		decided = copyChange(*c, true)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return decided, nil
+}
+
+func (s *Store) AddDeductionChangeComment(ctx context.Context, id int, author string, comment string) (*tax.ChangeComment, error) {
+	var added tax.ChangeComment
+	err := s.update(func(st *state) error {
+		idx := changeIndex(st, id)
+		if idx < 0 {
+			return fmt.Errorf("deduction change %d not found", id)
+		}
+
+		added = tax.ChangeComment{ID: st.next(seqComments), Author: author, Comment: comment, CreatedAt: timestamp()}
+		st.Changes[idx].Comments = append(st.Changes[idx].Comments, added)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &added, nil
+}
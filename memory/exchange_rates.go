@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+// ExchangeRate returns the latest rate of currency on or before date within
+// tax.RateWindow days, or nil when there is none.
+func (s *Store) ExchangeRate(ctx context.Context, currency string, date string) (*tax.ExchangeRate, error) {
+	stale, err := tax.StaleRateDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	var rate *tax.ExchangeRate
+	s.view(func(st *state) {
+		for _, r := range st.ExchangeRates {
+			if r.Currency != currency || r.Date > date || r.Date <= stale {
+				continue
+			}
+			if rate == nil || r.Date > rate.Date {
+				rate = &r
+			}
+		}
+	})
+	return rate, nil
+}
+
+// UpdateExchangeRates replaces the rates of the same date and currency.
+func (s *Store) UpdateExchangeRates(ctx context.Context, rates []tax.ExchangeRate) ([]tax.ExchangeRate, error) {
+	err := s.update(func(st *state) error {
+		for _, r := range rates {
+			idx := -1
+			for i, e := range st.ExchangeRates {
+				if e.Date == r.Date && e.Currency == r.Currency {
+					idx = i
+					break
+				}
+			}
+
+			if idx < 0 {
+				st.ExchangeRates = append(st.ExchangeRates, r)
+			} else {
+				st.ExchangeRates[idx].Rate = r.Rate
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
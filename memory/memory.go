@@ -0,0 +1,296 @@
+// Package memory keeps everything the server stores in memory, for local
+// development, demos and the ktax command. Open backs it with a JSON or
+// YAML file that every change is written to.
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/tax"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// timeLayout formats the timestamps the store sets.
+	timeLayout = time.RFC3339
+
+	seqChanges  = "deductionChanges"
+	seqComments = "deductionChangeComments"
+	seqUsers    = "adminUsers"
+	seqKeys     = "apiKeys"
+)
+
+// now is the clock the timestamps are read from.
+var now = time.Now
+
+type (
+	// user is an admin user with the password hash the JSON of
+	// auth.User leaves out.
+	user struct {
+		ID           int    `json:"id"`
+		Username     string `json:"username"`
+		Role         string `json:"role"`
+		PasswordHash string `json:"password_hash"`
+		CreatedAt    string `json:"created_at"`
+	}
+
+	// key is an API key with the hash the JSON of apikey.Key leaves out.
+	key struct {
+		ID                int     `json:"id"`
+		Name              string  `json:"name"`
+		Prefix            string  `json:"prefix"`
+		Hash              string  `json:"key_hash"`
+		RequestsPerMinute int     `json:"requestsPerMinute"`
+		RowsPerDay        int     `json:"rowsPerDay"`
+		CreatedAt         string  `json:"created_at"`
+		RevokedAt         *string `json:"revoked_at,omitempty"`
+	}
+
+	usage struct {
+		KeyID    int    `json:"api_key_id"`
+		Date     string `json:"date"`
+		Requests int    `json:"requests"`
+		Rows     int    `json:"rows"`
+	}
+
+	// sample keeps the allowances of a sample tax request the way the
+	// database does.
+	sample struct {
+		TotalIncome float64 `json:"totalIncome"`
+		WHT         float64 `json:"wht"`
+		Donation    float64 `json:"donation"`
+		KReceipt    float64 `json:"k-receipt"`
+	}
+
+	// state is everything the store keeps, and the content of its file.
+	// Sequences hold the last ID of every kind of record, so deleted IDs
+	// aren't given out again.
+	state struct {
+		Allowances    []tax.Allowances      `json:"allowances"`
+		ExchangeRates []tax.ExchangeRate    `json:"exchangeRates,omitempty"`
+		Samples       []sample              `json:"samples,omitempty"`
+		Changes       []tax.DeductionChange `json:"deductionChanges,omitempty"`
+		Users         []user                `json:"adminUsers,omitempty"`
+		Keys          []key                 `json:"apiKeys,omitempty"`
+		Usage         []usage               `json:"apiKeyUsage,omitempty"`
+		Sequences     map[string]int        `json:"sequences,omitempty"`
+	}
+
+	// Store is safe for concurrent use. Every change to a file-backed
+	// store is written to its file before it's seen, and discarded when
+	// the write fails.
+	Store struct {
+		mu    sync.RWMutex
+		state state
+		path  string
+	}
+)
+
+// New returns a store seeded with the allowances the migrations seed.
+func New() *Store {
+	s, _ := newStore(state{}, "")
+	return s
+}
+
+// Open returns a store backed by the file at path, in YAML when it ends in
+// .yaml or .yml and JSON otherwise. A missing file is created with the
+// defaults of New.
+func Open(path string) (*Store, error) {
+	st, err := load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s := New()
+		s.path = path
+		return s, s.save(s.state)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newStore(st, path)
+}
+
+// Read returns a store with the content of the file at path that doesn't
+// write its changes back. Allowances the file leaves out keep their
+// defaults.
+func Read(path string) (*Store, error) {
+	st, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	return newStore(st, "")
+}
+
+func newStore(st state, path string) (*Store, error) {
+	allowances := tax.DefaultAllowances()
+	for _, a := range st.Allowances {
+		idx := allowanceIndex(allowances, a.Type)
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown allowance type %q", a.Type)
+		}
+		a.ID = allowances[idx].ID
+		allowances[idx] = a
+	}
+	st.Allowances = allowances
+
+	if st.Sequences == nil {
+		st.Sequences = map[string]int{}
+	}
+	// Files edited by hand may hold IDs past their sequence.
+	for _, c := range st.Changes {
+		st.Sequences[seqChanges] = max(st.Sequences[seqChanges], c.ID)
+		for _, cc := range c.Comments {
+			st.Sequences[seqComments] = max(st.Sequences[seqComments], cc.ID)
+		}
+	}
+	for _, u := range st.Users {
+		st.Sequences[seqUsers] = max(st.Sequences[seqUsers], u.ID)
+	}
+	for _, k := range st.Keys {
+		st.Sequences[seqKeys] = max(st.Sequences[seqKeys], k.ID)
+	}
+	return &Store{state: st, path: path}, nil
+}
+
+func allowanceIndex(allowances []tax.Allowances, t string) int {
+	for i, a := range allowances {
+		if a.Type == t {
+			return i
+		}
+	}
+	return -1
+}
+
+func isYAML(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func load(path string) (state, error) {
+	var st state
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return st, err
+	}
+
+	if isYAML(path) {
+		// Round trip through JSON so both formats share the json tags.
+		var v interface{}
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return st, fmt.Errorf("%s: %w", path, err)
+		}
+		if b, err = json.Marshal(v); err != nil {
+			return st, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := json.Unmarshal(b, &st); err != nil {
+		return st, fmt.Errorf("%s: %w", path, err)
+	}
+	return st, nil
+}
+
+// save writes st to the file of s, through a temporary file so a failed
+// write leaves the old content.
+func (s *Store) save(st state) error {
+	if s.path == "" {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if isYAML(s.path) {
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		b = buf.Bytes()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// clone deep copies st, so a change can be discarded when it isn't saved.
+func (st state) clone() state {
+	b, err := json.Marshal(st)
+	if err != nil {
+		panic(err)
+	}
+
+	var c state
+	if err := json.Unmarshal(b, &c); err != nil {
+		panic(err)
+	}
+	if c.Sequences == nil {
+		c.Sequences = map[string]int{}
+	}
+	return c
+}
+
+// view calls fn with the state of s locked for reading.
+func (s *Store) view(fn func(st *state)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(&s.state)
+}
+
+// update calls fn with the state of s locked for writing, and keeps the
+// changes fn makes when it succeeds and they are saved. Only the state of
+// file-backed stores is copied first, so fn must check everything that can
+// fail before it changes st.
+func (s *Store) update(fn func(st *state) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.path == "" {
+		return fn(&s.state)
+	}
+
+	st := s.state.clone()
+	if err := fn(&st); err != nil {
+		return err
+	}
+	if err := s.save(st); err != nil {
+		return err
+	}
+	s.state = st
+	return nil
+}
+
+// next returns the next ID of the records of name.
+func (st *state) next(name string) int {
+	st.Sequences[name]++
+	return st.Sequences[name]
+}
+
+func timestamp() string {
+	return now().UTC().Format(timeLayout)
+}
@@ -0,0 +1,198 @@
+package memory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Gitong23/assessment-tax/storetest"
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func TestContract(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		storetest.Run(t, func(t *testing.T) storetest.Store { return New() })
+	})
+
+	for _, name := range []string{"store.json", "store.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			storetest.Run(t, func(t *testing.T) storetest.Store {
+				s, err := Open(filepath.Join(t.TempDir(), name))
+				if err != nil {
+					t.Fatalf("expected no error but got %v", err)
+				}
+				return s
+			})
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	for _, name := range []string{"store.json", "store.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			s, err := Open(path)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if _, err := os.Stat(path); err != nil {
+				t.Fatalf("expected the file to be created but got %v", err)
+			}
+
+			ctx := context.Background()
+			_, err = s.UpdateMaxAmountKreceipt(ctx, 70000)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			c, err := s.CreateDeductionChange(ctx, tax.DeductionChange{Type: "personal", Amount: 70000, Status: tax.ChangePending, SubmittedBy: "editor"})
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+
+			reopened, err := Open(path)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			k, _ := reopened.KreceiptAllowance(ctx)
+			if k.MaxAmount != 70000 {
+				t.Errorf("expected k-receipt to be saved as 70000 but got %v", k.MaxAmount)
+			}
+
+			next, err := reopened.CreateDeductionChange(ctx, tax.DeductionChange{Type: "personal", Amount: 70000, Status: tax.ChangePending, SubmittedBy: "editor"})
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			if next.ID != c.ID+1 {
+				t.Errorf("expected the next change to get ID %d but got %d", c.ID+1, next.ID)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		wantErr  string
+		personal float64
+		kReceipt float64
+	}{
+		{
+			name:     "Allowances left out keep their defaults",
+			file:     "config.yaml",
+			content:  "allowances:\n  - type: personal\n    init_amount: 100000\n    min_amount: 10000\n    max_amount: 100000\n",
+			personal: 100000,
+			kReceipt: 50000,
+		},
+		{
+			name:     "JSON",
+			file:     "config.json",
+			content:  `{"allowances": [{"type": "k-receipt", "max_amount": 90000}]}`,
+			personal: 60000,
+			kReceipt: 90000,
+		},
+		{
+			name:    "Unknown allowance type",
+			file:    "config.yaml",
+			content: "allowances:\n  - type: rent\n",
+			wantErr: `unknown allowance type "rent"`,
+		},
+		{
+			name:    "Invalid YAML",
+			file:    "config.yaml",
+			content: "allowances: [",
+			wantErr: "config.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := Read(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q but got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+
+			ctx := context.Background()
+			personal, _ := s.PersonalAllowance(ctx)
+			kReceipt, _ := s.KreceiptAllowance(ctx)
+			if personal.InitAmount != tt.personal || kReceipt.MaxAmount != tt.kReceipt {
+				t.Errorf("expected personal %v and k-receipt %v but got %v and %v", tt.personal, tt.kReceipt, personal.InitAmount, kReceipt.MaxAmount)
+			}
+
+			// Read doesn't write changes back.
+			_, err = s.UpdateInitPersonalAllowance(ctx, 20000)
+			if err != nil {
+				t.Fatalf("expected no error but got %v", err)
+			}
+			b, _ := os.ReadFile(path)
+			if string(b) != tt.content {
+				t.Errorf("expected the file to be left as it was but got %s", b)
+			}
+		})
+	}
+}
+
+func TestSaveFailed(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Open(filepath.Join(dir, "store.json"))
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, err = s.UpdateInitPersonalAllowance(ctx, 20000)
+	if err == nil {
+		t.Fatalf("expected an error saving to a removed directory")
+	}
+
+	personal, _ := s.PersonalAllowance(ctx)
+	if personal.InitAmount != 60000 {
+		t.Errorf("expected the failed change to be discarded but personal is %v", personal.InitAmount)
+	}
+}
+
+func TestConcurrentUsage(t *testing.T) {
+	s := New()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.RecordUsage(ctx, 1, "2024-05-01", 1, 2); err != nil {
+				t.Errorf("expected no error but got %v", err)
+			}
+			if _, err := s.PersonalAllowance(ctx); err != nil {
+				t.Errorf("expected no error but got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	usage, _ := s.Usage(ctx, 1)
+	if len(usage) != 1 || usage[0].Requests != 50 || usage[0].Rows != 100 {
+		t.Errorf("expected 50 requests and 100 rows but got %+v", usage)
+	}
+}
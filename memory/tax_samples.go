@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func (s *Store) SampleTaxRequests(ctx context.Context) ([]tax.TaxRequest, error) {
+	var samples []tax.TaxRequest
+	s.view(func(st *state) {
+		for _, sm := range st.Samples {
+			samples = append(samples, tax.TaxRequest{
+				TotalIncome: sm.TotalIncome,
+				WHT:         sm.WHT,
+				Allowances: []tax.AllowanceReq{
+					{AllowanceType: "donation", Amount: sm.Donation},
+					{AllowanceType: "k-receipt", Amount: sm.KReceipt},
+				},
+			})
+		}
+	})
+	return samples, nil
+}
+
+// UpdateSampleTaxRequests replaces the sample population.
+func (s *Store) UpdateSampleTaxRequests(ctx context.Context, samples []tax.TaxRequest) error {
+	return s.update(func(st *state) error {
+		st.Samples = nil
+		for _, t := range samples {
+			amounts := map[string]float64{}
+			for _, a := range t.Allowances {
+				amounts[a.AllowanceType] += a.Amount
+			}
+
+			st.Samples = append(st.Samples, sample{
+				TotalIncome: t.TotalIncome,
+				WHT:         t.WHT,
+				Donation:    amounts["donation"],
+				KReceipt:    amounts["k-receipt"],
+			})
+		}
+		return nil
+	})
+}
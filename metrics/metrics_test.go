@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware)
+	e.GET("/admin/deductions/changes/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/metrics", Handler())
+
+	for _, path := range []string{"/admin/deductions/changes/1", "/admin/deductions/changes/2", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	tests := []struct {
+		name string
+		line string
+		want bool
+	}{
+		{name: "Requests are labelled by route", line: `ktax_http_request_duration_seconds_count{code="200",method="GET",route="/admin/deductions/changes/:id"} 2`, want: true},
+		{name: "Unknown paths are not labelled by path", line: `route="/unknown"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(body, tt.line); got != tt.want {
+				t.Errorf("expected %s in metrics to be %v but got %v", tt.line, tt.want, got)
+			}
+		})
+	}
+}
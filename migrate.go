@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/migrations"
+	"github.com/Gitong23/assessment-tax/postgres"
+	"github.com/Gitong23/assessment-tax/sqlite"
+)
+
+const migrateUsage = "usage: migrate [up | down [steps] | status]"
+
+type migrator interface {
+	Migrate() error
+	Rollback(steps int) error
+	MigrationStatus() ([]migrations.Status, error)
+}
+
+// openMigrator opens the database of cfg without migrating it, with the
+// func that closes it.
+func openMigrator(cfg config.DB) (migrator, func() error, error) {
+	if cfg.Driver == config.StoreSQLite {
+		s, err := sqlite.Open(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, s.Db.Close, nil
+	}
+
+	p, err := postgres.Open(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, p.Db.Close, nil
+}
+
+// migrate runs the migrate subcommand against DATABASE_URL, with the
+// dialect of STORE_DRIVER.
+func migrate(args []string) error {
+	db, closeDB, err := openMigrator(config.New().DB)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	cmd := "up"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	switch cmd {
+	case "up":
+		return db.Migrate()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf(migrateUsage)
+			}
+		}
+		return db.Rollback(steps)
+	case "status":
+		status, err := db.MigrationStatus()
+		if err != nil {
+			return err
+		}
+		for _, s := range status {
+			applied := "pending"
+			if s.Applied {
+				applied = "applied " + s.AppliedAt
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	}
+	return fmt.Errorf(migrateUsage)
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	for _, dialect := range []string{Postgres, SQLite} {
+		t.Run(dialect, func(t *testing.T) {
+			migrations, err := Load(dialect)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for i, m := range migrations {
+				if m.Version != i+1 {
+					t.Errorf("expected migration version %d but got %d", i+1, m.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestDialectsMatch(t *testing.T) {
+	postgres, err := Load(Postgres)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sqlite, err := Load(SQLite)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(postgres) != len(sqlite) {
+		t.Fatalf("expected %d sqlite migrations but got %d", len(postgres), len(sqlite))
+	}
+	for i := range postgres {
+		if postgres[i].Version != sqlite[i].Version || postgres[i].Name != sqlite[i].Name {
+			t.Errorf("expected sqlite migration %d_%s but got %d_%s", postgres[i].Version, postgres[i].Name, sqlite[i].Version, sqlite[i].Name)
+		}
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "Missing down migration",
+			fsys: fstest.MapFS{
+				"test/0001_init.up.sql": {Data: []byte("SELECT 1")},
+			},
+		},
+		{
+			name: "Invalid file name",
+			fsys: fstest.MapFS{
+				"test/init.sql": {Data: []byte("SELECT 1")},
+			},
+		},
+		{
+			name: "Conflicting names",
+			fsys: fstest.MapFS{
+				"test/0001_init.up.sql":    {Data: []byte("SELECT 1")},
+				"test/0001_setup.down.sql": {Data: []byte("SELECT 1")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.fsys, "test")
+			if err == nil {
+				t.Errorf("expected error but got nil")
+			}
+		})
+	}
+}
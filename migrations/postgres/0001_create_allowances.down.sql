@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS allowances;
+
+DROP TYPE IF EXISTS allowance_type;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+  date DATE NOT NULL,
+  currency CHAR(3) NOT NULL,
+  rate DECIMAL(12, 6) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (date, currency)
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS admin_users (
+  id SERIAL PRIMARY KEY,
+  username VARCHAR(64) NOT NULL UNIQUE,
+  password_hash TEXT NOT NULL,
+  role VARCHAR(16) NOT NULL CHECK (role IN ('viewer', 'editor', 'approver')),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
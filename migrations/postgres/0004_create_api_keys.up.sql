@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+  id SERIAL PRIMARY KEY,
+  name VARCHAR(128) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  key_hash CHAR(64) NOT NULL UNIQUE,
+  requests_per_minute INTEGER NOT NULL,
+  rows_per_day INTEGER NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  revoked_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_key_usage (
+  api_key_id INTEGER NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+  date DATE NOT NULL,
+  requests INTEGER NOT NULL DEFAULT 0,
+  csv_rows INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (api_key_id, date)
+);
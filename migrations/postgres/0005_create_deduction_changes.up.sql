@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS deduction_changes (
+  id SERIAL PRIMARY KEY,
+  type allowance_type NOT NULL,
+  amount DECIMAL(10, 2) NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  submitted_by VARCHAR(64) NOT NULL,
+  decided_by VARCHAR(64),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  decided_at TIMESTAMP,
+  CHECK (decided_by <> submitted_by)
+);
+
+CREATE TABLE IF NOT EXISTS deduction_change_comments (
+  id SERIAL PRIMARY KEY,
+  change_id INTEGER NOT NULL REFERENCES deduction_changes (id) ON DELETE CASCADE,
+  author VARCHAR(64) NOT NULL,
+  comment TEXT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS tax_samples (
+  id SERIAL PRIMARY KEY,
+  total_income DECIMAL(14, 2) NOT NULL,
+  wht DECIMAL(14, 2) NOT NULL,
+  donation DECIMAL(14, 2) NOT NULL DEFAULT 0,
+  k_receipt DECIMAL(14, 2) NOT NULL DEFAULT 0,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS allowances_changed ON allowances;
+
+DROP FUNCTION IF EXISTS notify_allowances_changed();
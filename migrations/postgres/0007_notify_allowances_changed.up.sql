@@ -0,0 +1,10 @@
+CREATE OR REPLACE FUNCTION notify_allowances_changed() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('allowances_changed', '');
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER allowances_changed
+AFTER INSERT OR UPDATE OR DELETE ON allowances
+FOR EACH STATEMENT EXECUTE FUNCTION notify_allowances_changed();
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS allowances (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  type TEXT NOT NULL CHECK (type IN ('personal', 'donation', 'k-receipt')),
+  init_amount REAL NOT NULL,
+  min_amount REAL NOT NULL,
+  max_amount REAL NOT NULL,
+  limit_max_amount REAL NOT NULL,
+  created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
+
+INSERT INTO allowances (type, init_amount, min_amount, max_amount, limit_max_amount) VALUES
+('personal', 60000, 10000.00, 100000.00, 100000.00),
+('donation', 0, 0, 100000.00, 100000.00),
+('k-receipt', 0, 0, 50000.00, 100000.00);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  prefix TEXT NOT NULL,
+  key_hash TEXT NOT NULL UNIQUE,
+  requests_per_minute INTEGER NOT NULL,
+  rows_per_day INTEGER NOT NULL,
+  created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  revoked_at TEXT
+);
+
+CREATE TABLE IF NOT EXISTS api_key_usage (
+  api_key_id INTEGER NOT NULL REFERENCES api_keys (id) ON DELETE CASCADE,
+  date TEXT NOT NULL,
+  requests INTEGER NOT NULL DEFAULT 0,
+  csv_rows INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (api_key_id, date)
+);
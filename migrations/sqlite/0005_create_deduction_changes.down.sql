@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS deduction_change_comments;
+
+DROP TABLE IF EXISTS deduction_changes;
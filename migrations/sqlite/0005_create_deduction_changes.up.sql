@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS deduction_changes (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  type TEXT NOT NULL CHECK (type IN ('personal', 'donation', 'k-receipt')),
+  amount REAL NOT NULL,
+  status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  submitted_by TEXT NOT NULL,
+  decided_by TEXT,
+  created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now')),
+  decided_at TEXT,
+  CHECK (decided_by <> submitted_by)
+);
+
+CREATE TABLE IF NOT EXISTS deduction_change_comments (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  change_id INTEGER NOT NULL REFERENCES deduction_changes (id) ON DELETE CASCADE,
+  author TEXT NOT NULL,
+  comment TEXT NOT NULL,
+  created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
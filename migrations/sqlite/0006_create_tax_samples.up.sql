@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS tax_samples (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  total_income REAL NOT NULL,
+  wht REAL NOT NULL,
+  donation REAL NOT NULL DEFAULT 0,
+  k_receipt REAL NOT NULL DEFAULT 0,
+  created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
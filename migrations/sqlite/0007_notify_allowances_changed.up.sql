@@ -0,0 +1,3 @@
+-- SQLite has no notifications. The one process using the database drops
+-- its cached allowances itself when it changes them.
+SELECT 1;
@@ -0,0 +1,398 @@
+// Package openapi builds an OpenAPI 3 document whose schemas are read from
+// the Go types the handlers bind and respond with, so they can't drift
+// apart from the code.
+package openapi
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const Version = "3.0.3"
+
+type (
+	Document struct {
+		OpenAPI    string              `json:"openapi"`
+		Info       Info                `json:"info"`
+		Paths      map[string]PathItem `json:"paths"`
+		Components Components          `json:"components"`
+	}
+
+	Info struct {
+		Title       string `json:"title"`
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// PathItem maps the lower case methods of a path to their operations.
+	PathItem map[string]*Operation
+
+	Operation struct {
+		OperationID string                `json:"operationId"`
+		Summary     string                `json:"summary"`
+		Tags        []string              `json:"tags,omitempty"`
+		Parameters  []Parameter           `json:"parameters,omitempty"`
+		RequestBody *RequestBody          `json:"requestBody,omitempty"`
+		Responses   map[string]Response   `json:"responses"`
+		Security    []map[string][]string `json:"security,omitempty"`
+		Deprecated  bool                  `json:"deprecated,omitempty"`
+	}
+
+	Parameter struct {
+		Name        string  `json:"name"`
+		In          string  `json:"in"`
+		Description string  `json:"description,omitempty"`
+		Required    bool    `json:"required,omitempty"`
+		Schema      *Schema `json:"schema"`
+	}
+
+	RequestBody struct {
+		Required bool                 `json:"required"`
+		Content  map[string]MediaType `json:"content"`
+	}
+
+	MediaType struct {
+		Schema *Schema `json:"schema"`
+	}
+
+	Response struct {
+		Description string               `json:"description"`
+		Content     map[string]MediaType `json:"content,omitempty"`
+	}
+
+	Components struct {
+		Schemas         map[string]*Schema        `json:"schemas"`
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	SecurityScheme struct {
+		Type   string `json:"type"`
+		Scheme string `json:"scheme,omitempty"`
+		Name   string `json:"name,omitempty"`
+		In     string `json:"in,omitempty"`
+	}
+
+	Schema struct {
+		Ref                  string             `json:"$ref,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Description          string             `json:"description,omitempty"`
+		Nullable             bool               `json:"nullable,omitempty"`
+		Enum                 []string           `json:"enum,omitempty"`
+		Minimum              *float64           `json:"minimum,omitempty"`
+		Maximum              *float64           `json:"maximum,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+		OneOf                []*Schema          `json:"oneOf,omitempty"`
+	}
+)
+
+type (
+	// Op describes an operation the way routes are registered with echo.
+	// Request and the response values are zero values of the Go types
+	// they're bound to or encoded from.
+	Op struct {
+		Method  string
+		Path    string
+		Summary string
+		Tags    []string
+		// Security names the schemes any one of which authorizes the
+		// operation.
+		Security []string
+		Query    []Parameter
+		Request  interface{}
+		// Files are the multipart form fields of an upload.
+		Files     []string
+		Responses []Resp
+		// Deprecated operations have a successor clients should move to.
+		Deprecated bool
+	}
+
+	// Resp is a response of an operation. A nil Body has no content, and
+	// several Resps with the same status are alternatives.
+	Resp struct {
+		Status      int
+		Description string
+		Body        interface{}
+	}
+)
+
+// Spec builds a Document.
+type Spec struct {
+	doc Document
+	// errBody is the body of every error response.
+	errBody interface{}
+	errType string
+	routes  []string
+}
+
+// New returns a spec whose operations answer errors with errBody encoded
+// as errType.
+func New(info Info, errBody interface{}, errType string) *Spec {
+	return &Spec{
+		doc: Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   map[string]PathItem{},
+			Components: Components{
+				Schemas:         map[string]*Schema{},
+				SecuritySchemes: map[string]SecurityScheme{},
+			},
+		},
+		errBody: errBody,
+		errType: errType,
+	}
+}
+
+func (s *Spec) SecurityScheme(name string, scheme SecurityScheme) {
+	s.doc.Components.SecuritySchemes[name] = scheme
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// Add documents op.
+func (s *Spec) Add(op Op) {
+	path := pathParam.ReplaceAllString(op.Path, "{$1}")
+	o := &Operation{
+		OperationID: operationID(op.Method, op.Path),
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		Responses:   map[string]Response{},
+		Deprecated:  op.Deprecated,
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		o.Parameters = append(o.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	o.Parameters = append(o.Parameters, op.Query...)
+
+	switch {
+	case op.Request != nil:
+		o.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: s.Schema(op.Request)},
+		}}
+	case len(op.Files) > 0:
+		form := &Schema{Type: "object", Properties: map[string]*Schema{}, Required: op.Files}
+		for _, f := range op.Files {
+			form.Properties[f] = &Schema{Type: "array", Items: &Schema{Type: "string", Format: "binary"}}
+		}
+		o.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: form},
+		}}
+	}
+
+	for _, r := range op.Responses {
+		status := strconv.Itoa(r.Status)
+		res, ok := o.Responses[status]
+		if !ok {
+			res = Response{Description: r.Description}
+		}
+		if r.Body != nil {
+			res.Content = addAlternative(res.Content, "application/json", s.Schema(r.Body))
+		}
+		o.Responses[status] = res
+	}
+	o.Responses["default"] = Response{
+		Description: "Error",
+		Content:     map[string]MediaType{s.errType: {Schema: s.Schema(s.errBody)}},
+	}
+
+	for _, name := range op.Security {
+		o.Security = append(o.Security, map[string][]string{name: {}})
+	}
+
+	item, ok := s.doc.Paths[path]
+	if !ok {
+		item = PathItem{}
+		s.doc.Paths[path] = item
+	}
+	item[strings.ToLower(op.Method)] = o
+	s.routes = append(s.routes, op.Method+" "+op.Path)
+}
+
+// addAlternative adds schema to the content of type t, as one of several
+// schemas when there's one already.
+func addAlternative(content map[string]MediaType, t string, schema *Schema) map[string]MediaType {
+	if content == nil {
+		content = map[string]MediaType{}
+	}
+
+	existing, ok := content[t]
+	switch {
+	case !ok:
+		content[t] = MediaType{Schema: schema}
+	case existing.Schema.OneOf != nil:
+		existing.Schema.OneOf = append(existing.Schema.OneOf, schema)
+	default:
+		content[t] = MediaType{Schema: &Schema{OneOf: []*Schema{existing.Schema, schema}}}
+	}
+	return content
+}
+
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == ':' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// Routes returns the documented operations as "METHOD /path", with the path
+// written the way echo registers it.
+func (s *Spec) Routes() []string {
+	routes := append([]string(nil), s.routes...)
+	sort.Strings(routes)
+	return routes
+}
+
+func (s *Spec) Document() Document {
+	return s.doc
+}
+
+// Schema returns the schema of the type of v. Named struct types are added
+// to the components and referred to.
+func (s *Spec) Schema(v interface{}) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+func (s *Spec) schema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.schema(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		if _, ok := s.doc.Components.Schemas[t.Name()]; !ok {
+			// Added before its fields so that types referring to
+			// themselves end.
+			s.doc.Components.Schemas[t.Name()] = &Schema{}
+			*s.doc.Components.Schemas[t.Name()] = *s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + t.Name()}
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+// object returns the schema of the JSON object encoding/json makes of the
+// struct type t. A format tag sets the format of a field, such as "decimal"
+// for amounts encoded as strings, and a doc tag describes it.
+func (s *Spec) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded := s.object(f.Type)
+			for n, p := range embedded.Properties {
+				obj.Properties[n] = p
+			}
+			obj.Required = append(obj.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		prop := s.schema(f.Type)
+		if format := f.Tag.Get("format"); format != "" {
+			prop.Format = format
+		}
+		if doc := f.Tag.Get("doc"); doc != "" {
+			prop.Description = doc
+		}
+		if required := validate(prop, f.Tag.Get("validate")); required {
+			obj.Required = append(obj.Required, name)
+		}
+		obj.Properties[name] = prop
+	}
+	sort.Strings(obj.Required)
+	return obj
+}
+
+// validate adds the validator rules of tag to prop and reports whether
+// they make the field required.
+func validate(prop *Schema, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			prop.Enum = strings.Fields(param)
+		case "gte", "min":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				prop.Minimum = &n
+			}
+		case "lte", "max":
+			if n, err := strconv.ParseFloat(param, 64); err == nil {
+				prop.Maximum = &n
+			}
+		}
+	}
+	return required
+}
+
+// JSON serves the document.
+func (s *Spec) JSON(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.doc)
+}
+
+// Docs serves a page browsing the document served by JSON at openapi.json
+// next to it.
+func (s *Spec) Docs(c echo.Context) error {
+	return c.HTML(http.StatusOK, fmt.Sprintf(docsPage, html.EscapeString(s.doc.Info.Title)))
+}
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="docs"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "openapi.json", dom_id: "#docs"});
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Gitong23/assessment-tax/auth"
+)
+
+func (p *Postgres) AdminUser(ctx context.Context, username string) (_ *auth.User, err error) {
+	ctx, done := p.bound(ctx, "AdminUser", &err)
+	defer done()
+
+	row := p.Db.QueryRowContext(ctx, "SELECT id, username, role, password_hash, created_at FROM admin_users WHERE username = $1", username)
+
+	var u auth.User
+	err = row.Scan(&u.ID, &u.Username, &u.Role, &u.PasswordHash, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (p *Postgres) AdminUsers(ctx context.Context) (_ []auth.User, err error) {
+	ctx, done := p.bound(ctx, "AdminUsers", &err)
+	defer done()
+
+	rows, err := p.Db.QueryContext(ctx, "SELECT id, username, role, password_hash, created_at FROM admin_users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []auth.User{}
+	for rows.Next() {
+		var u auth.User
+		err := rows.Scan(&u.ID, &u.Username, &u.Role, &u.PasswordHash, &u.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (p *Postgres) CreateAdminUser(ctx context.Context, u auth.User) (_ *auth.User, err error) {
+	ctx, done := p.bound(ctx, "CreateAdminUser", &err)
+	defer done()
+
+	row := p.Db.QueryRowContext(ctx, "INSERT INTO admin_users (username, password_hash, role) VALUES ($1, $2, $3) RETURNING id, created_at", u.Username, u.PasswordHash, u.Role)
+
+	err = row.Scan(&u.ID, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (p *Postgres) DeleteAdminUser(ctx context.Context, username string) (err error) {
+	ctx, done := p.bound(ctx, "DeleteAdminUser", &err)
+	defer done()
+
+	_, err = p.Db.ExecContext(ctx, "DELETE FROM admin_users WHERE username = $1", username)
+	return err
+}
@@ -1,6 +1,10 @@
 package postgres
 
-import "github.com/Gitong23/assessment-tax/tax"
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
 
 type Allowances struct {
 	ID             int     `posgres:"id"`
@@ -12,8 +16,11 @@ type Allowances struct {
 	CreatedAt      string  `posgres:"created_at"`
 }
 
-func (p *Postgres) PersonalAllowance() (*tax.Allowances, error) {
-	row, err := p.Db.Query("SELECT * FROM allowances WHERE type = 'personal'")
+func (p *Postgres) PersonalAllowance(ctx context.Context) (_ *tax.Allowances, err error) {
+	ctx, done := p.bound(ctx, "PersonalAllowance", &err)
+	defer done()
+
+	row, err := p.Db.QueryContext(ctx, "SELECT * FROM allowances WHERE type = 'personal'")
 	if err != nil {
 		return nil, err
 	}
@@ -35,11 +42,14 @@ func (p *Postgres) PersonalAllowance() (*tax.Allowances, error) {
 		}
 	}
 
-	return &personal, nil
+	return &personal, row.Err()
 }
 
-func (p *Postgres) DonationAllowance() (*tax.Allowances, error) {
-	row, err := p.Db.Query("SELECT * FROM allowances WHERE type = 'donation'")
+func (p *Postgres) DonationAllowance(ctx context.Context) (_ *tax.Allowances, err error) {
+	ctx, done := p.bound(ctx, "DonationAllowance", &err)
+	defer done()
+
+	row, err := p.Db.QueryContext(ctx, "SELECT * FROM allowances WHERE type = 'donation'")
 
 	if err != nil {
 		return nil, err
@@ -62,11 +72,14 @@ func (p *Postgres) DonationAllowance() (*tax.Allowances, error) {
 		}
 	}
 
-	return &d, nil
+	return &d, row.Err()
 }
 
-func (p *Postgres) KreceiptAllowance() (*tax.Allowances, error) {
-	row, err := p.Db.Query("SELECT * FROM allowances WHERE type = 'k-receipt'")
+func (p *Postgres) KreceiptAllowance(ctx context.Context) (_ *tax.Allowances, err error) {
+	ctx, done := p.bound(ctx, "KreceiptAllowance", &err)
+	defer done()
+
+	row, err := p.Db.QueryContext(ctx, "SELECT * FROM allowances WHERE type = 'k-receipt'")
 
 	if err != nil {
 		return nil, err
@@ -89,22 +102,28 @@ func (p *Postgres) KreceiptAllowance() (*tax.Allowances, error) {
 		}
 	}
 
-	return &k, nil
+	return &k, row.Err()
 }
 
-func (p *Postgres) UpdateInitPersonalAllowance(amount float64) (*tax.Allowances, error) {
-	_, err := p.Db.Exec("UPDATE allowances SET init_amount = $1 WHERE type = 'personal'", amount)
+func (p *Postgres) UpdateInitPersonalAllowance(ctx context.Context, amount float64) (_ *tax.Allowances, err error) {
+	boundCtx, done := p.bound(ctx, "UpdateInitPersonalAllowance", &err)
+	defer done()
+
+	_, err = p.Db.ExecContext(boundCtx, "UPDATE allowances SET init_amount = $1 WHERE type = 'personal'", amount)
 	if err != nil {
 		return nil, err
 	}
 
-	return p.PersonalAllowance()
+	return p.PersonalAllowance(ctx)
 }
 
-func (p *Postgres) UpdateMaxAmountKreceipt(amount float64) (*tax.Allowances, error) {
-	_, err := p.Db.Exec("UPDATE allowances SET max_amount = $1 WHERE type = 'k-receipt'", amount)
+func (p *Postgres) UpdateMaxAmountKreceipt(ctx context.Context, amount float64) (_ *tax.Allowances, err error) {
+	boundCtx, done := p.bound(ctx, "UpdateMaxAmountKreceipt", &err)
+	defer done()
+
+	_, err = p.Db.ExecContext(boundCtx, "UPDATE allowances SET max_amount = $1 WHERE type = 'k-receipt'", amount)
 	if err != nil {
 		return nil, err
 	}
-	return p.KreceiptAllowance()
+	return p.KreceiptAllowance(ctx)
 }
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+const changeColumns = "id, type, amount, status, submitted_by, decided_by, created_at, decided_at"
+
+// deductionColumns is the allowances column each deduction change sets.
+var deductionColumns = map[string]string{
+	"personal":  "init_amount",
+	"k-receipt": "max_amount",
+}
+
+func scanDeductionChange(row scanner) (*tax.DeductionChange, error) {
+	var c tax.DeductionChange
+	var decidedBy, decidedAt sql.NullString
+	err := row.Scan(&c.ID, &c.Type, &c.Amount, &c.Status, &c.SubmittedBy, &decidedBy, &c.CreatedAt, &decidedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.DecidedBy = decidedBy.String
+	c.DecidedAt = decidedAt.String
+
+	return &c, nil
+}
+
+func (p *Postgres) CreateDeductionChange(ctx context.Context, change tax.DeductionChange) (_ *tax.DeductionChange, err error) {
+	ctx, done := p.bound(ctx, "CreateDeductionChange", &err)
+	defer done()
+
+	row := p.Db.QueryRowContext(ctx, `INSERT INTO deduction_changes (type, amount, status, submitted_by)
+		VALUES ($1, $2, $3, $4) RETURNING `+changeColumns, change.Type, change.Amount, change.Status, change.SubmittedBy)
+	return scanDeductionChange(row)
+}
+
+func (p *Postgres) DeductionChanges(ctx context.Context, status string) (_ []tax.DeductionChange, err error) {
+	ctx, done := p.bound(ctx, "DeductionChanges", &err)
+	defer done()
+
+	rows, err := p.Db.QueryContext(ctx, "SELECT "+changeColumns+" FROM deduction_changes WHERE $1 = '' OR status = $1 ORDER BY id DESC", status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []tax.DeductionChange{}
+	for rows.Next() {
+		c, err := scanDeductionChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, *c)
+	}
+
+	return changes, rows.Err()
+}
+
+func (p *Postgres) DeductionChange(ctx context.Context, id int) (_ *tax.DeductionChange, err error) {
+	ctx, done := p.bound(ctx, "DeductionChange", &err)
+	defer done()
+
+	c, err := scanDeductionChange(p.Db.QueryRowContext(ctx, "SELECT "+changeColumns+" FROM deduction_changes WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := p.Db.QueryContext(ctx, "SELECT id, author, comment, created_at FROM deduction_change_comments WHERE change_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cc tax.ChangeComment
+		err := rows.Scan(&cc.ID, &cc.Author, &cc.Comment, &cc.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		c.Comments = append(c.Comments, cc)
+	}
+
+	return c, rows.Err()
+}
+
+func (p *Postgres) DecideDeductionChange(ctx context.Context, id int, status string, by string) (_ *tax.DeductionChange, err error) {
+	boundCtx, done := p.bound(ctx, "DecideDeductionChange", &err)
+	defer done()
+
+	tx, err := p.Db.BeginTx(boundCtx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(boundCtx, `UPDATE deduction_changes SET status = $2, decided_by = $3, decided_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending' RETURNING `+changeColumns, id, status, by)
+	c, err := scanDeductionChange(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Status == tax.ChangeApproved {
+		column, ok := deductionColumns[c.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown deduction type %s", c.Type)
+		}
+
+		_, err = tx.ExecContext(boundCtx, fmt.Sprintf("UPDATE allowances SET %s = $1 WHERE type = $2", column), c.Amount, c.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.DeductionChange(ctx, id)
+}
+
+func (p *Postgres) AddDeductionChangeComment(ctx context.Context, id int, author string, comment string) (_ *tax.ChangeComment, err error) {
+	ctx, done := p.bound(ctx, "AddDeductionChangeComment", &err)
+	defer done()
+
+	row := p.Db.QueryRowContext(ctx, `INSERT INTO deduction_change_comments (change_id, author, comment)
+		VALUES ($1, $2, $3) RETURNING id, author, comment, created_at`, id, author, comment)
+
+	var cc tax.ChangeComment
+	err = row.Scan(&cc.ID, &cc.Author, &cc.Comment, &cc.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cc, nil
+}
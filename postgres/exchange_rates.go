@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+const dateLayout = "2006-01-02"
+
+func (p *Postgres) ExchangeRate(ctx context.Context, currency string, date string) (_ *tax.ExchangeRate, err error) {
+	ctx, done := p.bound(ctx, "ExchangeRate", &err)
+	defer done()
+
+	row := p.Db.QueryRowContext(ctx, `SELECT date, currency, rate FROM exchange_rates
+		WHERE currency = $1 AND date <= $2 AND date > $2::date - $3::integer ORDER BY date DESC LIMIT 1`, currency, date, tax.RateWindow)
+
+	var r tax.ExchangeRate
+	var d time.Time
+	err = row.Scan(&d, &r.Currency, &r.Rate)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.Date = d.Format(dateLayout)
+
+	return &r, nil
+}
+
+func (p *Postgres) UpdateExchangeRates(ctx context.Context, rates []tax.ExchangeRate) (_ []tax.ExchangeRate, err error) {
+	ctx, done := p.bound(ctx, "UpdateExchangeRates", &err)
+	defer done()
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, r := range rates {
+		_, err := tx.ExecContext(ctx, `INSERT INTO exchange_rates (date, currency, rate) VALUES ($1, $2, $3)
+			ON CONFLICT (date, currency) DO UPDATE SET rate = EXCLUDED.rate`, r.Date, r.Currency, r.Rate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/migrations"
+	"github.com/lib/pq"
+)
+
+// migrationLock is the advisory lock key that keeps replicas starting at the
+// same time from applying the same migration twice.
+const migrationLock = 0x6b746178
+
+func (p *Postgres) createMigrationsTable() error {
+	_, err := p.Db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	)`)
+	return err
+}
+
+func (p *Postgres) appliedMigrations() (map[int]string, error) {
+	rows, err := p.Db.Query("SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]string{}
+	for rows.Next() {
+		var version int
+		var at string
+		err := rows.Scan(&version, &at)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// isBaseline reports whether the database was created from the schema
+// that used to be mounted as init.sql, which is what the first migration
+// creates.
+func isBaseline(tx *sql.Tx, m migrations.Migration) (bool, error) {
+	if m.Version != 1 {
+		return false, nil
+	}
+
+	var exists bool
+	err := tx.QueryRow("SELECT to_regclass('allowances') IS NOT NULL").Scan(&exists)
+	return exists, err
+}
+
+func (p *Postgres) apply(m migrations.Migration, up bool) error {
+	tx, err := p.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", migrationLock)
+	if err != nil {
+		return err
+	}
+
+	var applied bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.Version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+
+	// Another replica got here first.
+	if applied == up {
+		return nil
+	}
+
+	if up {
+		baseline, err := isBaseline(tx, m)
+		if err != nil {
+			return err
+		}
+
+		if !baseline {
+			_, err = tx.Exec(m.Up)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = tx.Exec(m.Down)
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = $1", m.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Migrate applies every migration that hasn't been applied yet.
+func (p *Postgres) Migrate() error {
+	all, err := migrations.Load(migrations.Postgres)
+	if err != nil {
+		return err
+	}
+
+	err = p.createMigrationsTable()
+	if err != nil {
+		return err
+	}
+
+	applied, err := p.appliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range all {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := p.apply(m, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback reverts the last steps applied migrations.
+func (p *Postgres) Rollback(steps int) error {
+	all, err := migrations.Load(migrations.Postgres)
+	if err != nil {
+		return err
+	}
+
+	err = p.createMigrationsTable()
+	if err != nil {
+		return err
+	}
+
+	applied, err := p.appliedMigrations()
+	if err != nil {
+		return err
+	}
+
+	for i := len(all) - 1; i >= 0 && steps > 0; i-- {
+		if _, ok := applied[all[i].Version]; !ok {
+			continue
+		}
+
+		err := p.apply(all[i], false)
+		if err != nil {
+			return err
+		}
+		steps--
+	}
+	return nil
+}
+
+// PendingMigrations returns how many migrations haven't been applied yet
+// without changing the schema.
+func (p *Postgres) PendingMigrations(ctx context.Context) (_ int, err error) {
+	ctx, done := p.bound(ctx, "PendingMigrations", &err)
+	defer done()
+
+	all, err := migrations.Load(migrations.Postgres)
+	if err != nil {
+		return 0, err
+	}
+
+	var exists bool
+	err = p.Db.QueryRowContext(ctx, "SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return len(all), nil
+	}
+
+	versions := make([]int64, len(all))
+	for i, m := range all {
+		versions[i] = int64(m.Version)
+	}
+
+	var applied int
+	err = p.Db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_migrations WHERE version = ANY($1)", pq.Array(versions)).Scan(&applied)
+	if err != nil {
+		return 0, err
+	}
+	return len(all) - applied, nil
+}
+
+func (p *Postgres) MigrationStatus() ([]migrations.Status, error) {
+	all, err := migrations.Load(migrations.Postgres)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.createMigrationsTable()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := p.appliedMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return migrations.Statuses(all, applied), nil
+}
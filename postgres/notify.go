@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// AllowancesChanged is the channel the allowances trigger notifies after
+// every committed change to the allowances table.
+const AllowancesChanged = "allowances_changed"
+
+const (
+	listenMinReconnect = 100 * time.Millisecond
+	listenMaxReconnect = 10 * time.Second
+
+	// listenPing is how often an idle listener checks its connection is
+	// still alive.
+	listenPing = 30 * time.Second
+)
+
+// Listener receives the notifications of a channel.
+type Listener struct {
+	l         *pq.Listener
+	connected atomic.Bool
+}
+
+// Listen calls fn for every notification on channel. It also calls fn after
+// the connection is re-established, since notifications sent while it was
+// down are lost.
+func (p *Postgres) Listen(channel string, fn func()) (*Listener, error) {
+	listener := &Listener{}
+	listener.l = pq.NewListener(p.url, listenMinReconnect, listenMaxReconnect, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventConnected, pq.ListenerEventReconnected:
+			listener.connected.Store(true)
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			listener.connected.Store(false)
+		}
+
+		if err != nil {
+			slog.Warn("listener connection failed", "channel", channel, "error", err)
+		}
+	})
+
+	err := listener.l.Listen(channel)
+	if err != nil {
+		listener.l.Close()
+		return nil, err
+	}
+
+	go func() {
+		for {
+			select {
+			// pq sends a nil notification after reconnecting, which
+			// calls fn as well.
+			case _, ok := <-listener.l.Notify:
+				if !ok {
+					return
+				}
+				fn()
+			case <-time.After(listenPing):
+				go listener.l.Ping()
+			}
+		}
+	}()
+	return listener, nil
+}
+
+// Connected reports whether the listener is connected, so that it won't
+// miss notifications.
+func (l *Listener) Connected() bool {
+	return l.connected.Load()
+}
+
+func (l *Listener) Close() error {
+	return l.l.Close()
+}
@@ -1,30 +1,144 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/Gitong23/assessment-tax/config"
-	_ "github.com/lib/pq"
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/metrics"
+	"github.com/Gitong23/assessment-tax/tracing"
+	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 type Postgres struct {
-	Db *sql.DB
+	Db      *sql.DB
+	url     string
+	timeout time.Duration
 }
 
-func New() (*Postgres, error) {
-	databaseSource := fmt.Sprintf("%s", config.New().DB.Url)
-	fmt.Println(databaseSource)
+const (
+	connectMinBackoff = 500 * time.Millisecond
+	connectMaxBackoff = 10 * time.Second
+)
 
-	db, err := sql.Open("postgres", databaseSource)
+// Open connects to the database without touching its schema.
+func Open(cfg config.DB) (*Postgres, error) {
+	db, err := sql.Open("postgres", cfg.Url)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	err = connect(db, cfg.ConnectTimeout)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Postgres{Db: db, url: cfg.Url, timeout: cfg.QueryTimeout}, nil
+}
+
+// connect pings db until it answers, backing off between attempts, and
+// gives up once timeout has passed.
+func connect(db *sql.DB, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	backoff := connectMinBackoff
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), connectMaxBackoff)
+		err := db.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("connecting to database: %w", err)
+		}
+
+		slog.Warn("database not ready", "retryIn", backoff.String(), "error", err)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, connectMaxBackoff)
+	}
+}
+
+// Ping checks the database answers within the query timeout.
+func (p *Postgres) Ping(ctx context.Context) (err error) {
+	ctx, done := p.bound(ctx, "Ping", &err)
+	defer done()
+
+	return p.Db.PingContext(ctx)
+}
+
+func New() (*Postgres, error) {
+	cfg := config.New().DB
+
+	p, err := Open(cfg)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+
+	if cfg.AutoMigrate {
+		err = p.Migrate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// unavailable reports whether err means the database couldn't be reached
+// or didn't answer in time, rather than that the query failed.
+func unavailable(err error) bool {
+	if err == nil || errors.Is(err, helper.ErrStoreUnavailable) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Connection exceptions, too many connections and server shutdowns.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code := string(pqErr.Code)
+		return strings.HasPrefix(code, "08") || code == "53300" || strings.HasPrefix(code, "57P")
+	}
+	return false
+}
+
+// bound starts a span for the store method and limits ctx to the
+// configured query timeout. The returned func releases ctx, observes how
+// long the method took, wraps *err in helper.ErrStoreUnavailable when the
+// database couldn't be reached and ends the span.
+func (p *Postgres) bound(ctx context.Context, method string, err *error) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "postgres."+method, semconv.DBSystemPostgreSQL)
+	cancel := func() {}
+	if p.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+	}
+
+	return ctx, func() {
+		cancel()
+		metrics.DBQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		if unavailable(*err) {
+			*err = fmt.Errorf("%w: %v", helper.ErrStoreUnavailable, *err)
+		}
+		tracing.End(span, *err)
 	}
-	return &Postgres{Db: db}, nil
 }
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/migrations"
+	"github.com/Gitong23/assessment-tax/storetest"
+	"github.com/lib/pq"
+)
+
+func TestUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "No error", err: nil, want: false},
+		{name: "Query timeout", err: context.DeadlineExceeded, want: true},
+		{name: "Connection refused", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "Too many connections", err: &pq.Error{Code: "53300"}, want: true},
+		{name: "Admin shutdown", err: &pq.Error{Code: "57P01"}, want: true},
+		{name: "Unique violation", err: &pq.Error{Code: "23505"}, want: false},
+		{name: "Client gone", err: context.Canceled, want: false},
+		{name: "Wrapped query timeout", err: fmt.Errorf("scan: %w", context.DeadlineExceeded), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unavailable(tt.err)
+			if got != tt.want {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestContract resets the database of TEST_DATABASE_URL before every part
+// of the contract, and is skipped without one.
+func TestContract(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL isn't set")
+	}
+
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		p, err := Open(config.DB{Url: url, ConnectTimeout: 10 * time.Second, QueryTimeout: 5 * time.Second, MaxOpenConns: 5, MaxIdleConns: 5})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		t.Cleanup(func() { p.Db.Close() })
+
+		all, err := migrations.Load(migrations.Postgres)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if err := p.Rollback(len(all)); err != nil {
+			t.Fatalf("expected no error resetting the database but got %v", err)
+		}
+		if err := p.Migrate(); err != nil {
+			t.Fatalf("expected no error migrating the database but got %v", err)
+		}
+		return p
+	})
+}
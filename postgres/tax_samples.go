@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func (p *Postgres) SampleTaxRequests(ctx context.Context) (_ []tax.TaxRequest, err error) {
+	ctx, done := p.bound(ctx, "SampleTaxRequests", &err)
+	defer done()
+
+	rows, err := p.Db.QueryContext(ctx, "SELECT total_income, wht, donation, k_receipt FROM tax_samples ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []tax.TaxRequest
+	for rows.Next() {
+		var t tax.TaxRequest
+		var donation, kReceipt float64
+		err := rows.Scan(&t.TotalIncome, &t.WHT, &donation, &kReceipt)
+		if err != nil {
+			return nil, err
+		}
+
+		t.Allowances = []tax.AllowanceReq{
+			{AllowanceType: "donation", Amount: donation},
+			{AllowanceType: "k-receipt", Amount: kReceipt},
+		}
+		samples = append(samples, t)
+	}
+
+	return samples, rows.Err()
+}
+
+// UpdateSampleTaxRequests replaces the sample population.
+func (p *Postgres) UpdateSampleTaxRequests(ctx context.Context, samples []tax.TaxRequest) (err error) {
+	ctx, done := p.bound(ctx, "UpdateSampleTaxRequests", &err)
+	defer done()
+
+	tx, err := p.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM tax_samples")
+	if err != nil {
+		return err
+	}
+
+	for _, t := range samples {
+		amounts := map[string]float64{}
+		for _, a := range t.Allowances {
+			amounts[a.AllowanceType] += a.Amount
+		}
+
+		_, err := tx.ExecContext(ctx, "INSERT INTO tax_samples (total_income, wht, donation, k_receipt) VALUES ($1, $2, $3, $4)",
+			t.TotalIncome, t.WHT, amounts["donation"], amounts["k-receipt"])
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,69 @@
+package problem
+
+import (
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/i18n"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain names the service in the ErrorInfo of the gRPC errors, whose
+// reason is the code of the problem.
+const Domain = "ktax"
+
+var grpcCodes = map[int]grpccodes.Code{
+	http.StatusBadRequest:            grpccodes.InvalidArgument,
+	http.StatusUnauthorized:          grpccodes.Unauthenticated,
+	http.StatusForbidden:             grpccodes.PermissionDenied,
+	http.StatusNotFound:              grpccodes.NotFound,
+	http.StatusConflict:              grpccodes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: grpccodes.ResourceExhausted,
+	http.StatusTooManyRequests:       grpccodes.ResourceExhausted,
+	http.StatusInternalServerError:   grpccodes.Internal,
+	http.StatusServiceUnavailable:    grpccodes.Unavailable,
+}
+
+// GRPCCode returns the gRPC code of status.
+func GRPCCode(status int) grpccodes.Code {
+	if code, ok := grpcCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return grpccodes.Internal
+	}
+	return grpccodes.InvalidArgument
+}
+
+// GRPC returns err as a gRPC status error in the language of p. Its details
+// carry the code of err and, for invalid input, the fields at fault.
+func GRPC(err *Error, p *i18n.Printer) error {
+	l := Localize(err, p)
+	st := status.New(GRPCCode(err.Status), l.Message)
+
+	info := &errdetails.ErrorInfo{Reason: err.Code, Domain: Domain}
+	if len(l.Fields) == 0 {
+		return withDetails(st, info)
+	}
+
+	bad := &errdetails.BadRequest{}
+	for _, f := range l.Fields {
+		bad.FieldViolations = append(bad.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Pointer,
+			Description: f.Message,
+		})
+	}
+	return withDetails(st, info, bad)
+}
+
+// withDetails returns st with details, or without them when they can't be
+// encoded.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,282 @@
+// Package problem answers failed requests with RFC 7807 problem details
+// that carry a stable code and, for invalid input, the fields at fault.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+)
+
+const MIMEProblemJSON = "application/problem+json"
+
+// Codes shared by every handler. Handlers add their own codes for the
+// failures only they report.
+const (
+	CodeBadRequest         = "bad_request"
+	CodeInvalidBody        = "invalid_body"
+	CodeValidationFailed   = "validation_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeMethodNotAllowed   = "method_not_allowed"
+	CodeConflict           = "conflict"
+	CodeTooLarge           = "payload_too_large"
+	CodeTooManyRequests    = "too_many_requests"
+	CodeInternal           = "internal_error"
+	CodeServiceUnavailable = "service_unavailable"
+)
+
+var codes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodeTooLarge,
+	http.StatusTooManyRequests:       CodeTooManyRequests,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+}
+
+type (
+	// FieldError points at one invalid field of the request. Limit is the
+	// bound the field broke, when the rule has one. Message is an English
+	// format, translated and formatted with Args when it's sent.
+	FieldError struct {
+		Pointer string        `json:"pointer"`
+		Rule    string        `json:"rule"`
+		Limit   string        `json:"limit,omitempty"`
+		Message string        `json:"message"`
+		Args    []interface{} `json:"-"`
+	}
+
+	// Problem is the body of every error response. Title, Detail and the
+	// field messages are in the language of the request, while Message
+	// stays in English for clients written against the old
+	// {"message": ...} body.
+	Problem struct {
+		Type      string       `json:"type"`
+		Title     string       `json:"title"`
+		Status    int          `json:"status"`
+		Detail    string       `json:"detail"`
+		Instance  string       `json:"instance,omitempty"`
+		Code      string       `json:"code"`
+		Message   string       `json:"message"`
+		RequestID string       `json:"requestId,omitempty"`
+		Errors    []FieldError `json:"errors,omitempty"`
+	}
+
+	// Error is an error that knows the problem to respond with. Message
+	// is an English format, like the one of FieldError.
+	Error struct {
+		Status  int
+		Code    string
+		Message string
+		Args    []interface{}
+		Fields  []FieldError
+	}
+)
+
+func (e *Error) Error() string {
+	if len(e.Args) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf(e.Message, e.Args...)
+}
+
+// With sets the arguments the message of e is formatted with.
+func (e *Error) With(args ...interface{}) *Error {
+	e.Args = args
+	return e
+}
+
+func New(status int, code, message string, fields ...FieldError) *Error {
+	return &Error{Status: status, Code: code, Message: message, Fields: fields}
+}
+
+// Invalid returns a bad request caused by the given fields.
+func Invalid(code, message string, fields ...FieldError) *Error {
+	return New(http.StatusBadRequest, code, message, fields...)
+}
+
+// Field returns the error of the field at pointer.
+func Field(pointer, rule, limit, message string, args ...interface{}) FieldError {
+	return FieldError{Pointer: pointer, Rule: rule, Limit: limit, Message: message, Args: args}
+}
+
+// Code returns the generic code of status.
+func Code(status int) string {
+	if code, ok := codes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// From returns err as a problem, or a problem with status and the generic
+// code of status when err isn't one.
+func From(err error, status int) *Error {
+	var p *Error
+	if errors.As(err, &p) {
+		return p
+	}
+	return New(status, Code(status), err.Error())
+}
+
+// Nest prefixes the field pointers of err with prefix, for errors raised
+// by a part of the request that doesn't know where it sits.
+func Nest(err error, prefix string) error {
+	var p *Error
+	if !errors.As(err, &p) {
+		return err
+	}
+
+	nested := *p
+	nested.Fields = make([]FieldError, len(p.Fields))
+	for i, f := range p.Fields {
+		f.Pointer = prefix + f.Pointer
+		nested.Fields[i] = f
+	}
+	return &nested
+}
+
+// Bind returns the problem of a request body echo couldn't bind.
+func Bind(err error) *Error {
+	p := Invalid(CodeInvalidBody, "Invalid request body")
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		err = he.Internal
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		pointer := "/" + strings.ReplaceAll(typeErr.Field, ".", "/")
+		p.Fields = append(p.Fields, Field(pointer, "type", typeErr.Type.String(), "Must be a %s", typeErr.Type.String()))
+	}
+	return p
+}
+
+var index = regexp.MustCompile(`\[(\d+)\]`)
+
+// pointer turns a validator namespace such as TaxRequest.allowances[0].amount
+// into the JSON pointer /allowances/0/amount.
+func pointer(namespace string) string {
+	_, path, _ := strings.Cut(namespace, ".")
+	path = index.ReplaceAllString(path, ".$1")
+	return "/" + strings.ReplaceAll(path, ".", "/")
+}
+
+// ruleMessages are the messages of the validator rules, formatted with the
+// parameter of the rule.
+var ruleMessages = map[string]string{
+	"oneof": "Must be one of %s",
+	"gte":   "Must be at least %s",
+	"min":   "Must be at least %s",
+	"lte":   "Must be at most %s",
+	"max":   "Must be at most %s",
+	"gt":    "Must be more than %s",
+}
+
+func validationField(e validator.FieldError) FieldError {
+	if message, ok := ruleMessages[e.Tag()]; ok {
+		return Field(pointer(e.Namespace()), e.Tag(), e.Param(), message, e.Param())
+	}
+	if e.Tag() == "required" {
+		return Field(pointer(e.Namespace()), e.Tag(), "", "Is required")
+	}
+	return Field(pointer(e.Namespace()), e.Tag(), e.Param(), "Invalid value")
+}
+
+// Validation returns the problem of the errors the validator found, keeping
+// each of them as a field error.
+func Validation(err error) *Error {
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return Invalid(CodeInvalidBody, "Invalid request body")
+	}
+
+	p := Invalid(CodeValidationFailed, "Invalid request body")
+	for _, e := range errs {
+		p.Fields = append(p.Fields, validationField(e))
+	}
+	return p
+}
+
+// JSON responds with the problem of err in the language of the request.
+func JSON(c echo.Context, err *Error) error {
+	p := i18n.For(c)
+	l := Localize(err, p)
+
+	c.Response().Header().Set(echo.HeaderContentType, MIMEProblemJSON)
+	return c.JSON(err.Status, Problem{
+		Type:      "/problems/" + err.Code,
+		Title:     p.Sprintf(http.StatusText(err.Status)),
+		Status:    err.Status,
+		Detail:    l.Message,
+		Instance:  c.Request().URL.Path,
+		Code:      err.Code,
+		Message:   err.Error(),
+		RequestID: logger.RequestID(c),
+		Errors:    l.Fields,
+	})
+}
+
+// Localize returns a copy of err with its message and the messages of its
+// fields translated by p and formatted.
+func Localize(err *Error, p *i18n.Printer) *Error {
+	l := &Error{Status: err.Status, Code: err.Code, Message: p.Sprintf(err.Message, err.Args...)}
+	for _, f := range err.Fields {
+		l.Fields = append(l.Fields, FieldError{
+			Pointer: f.Pointer,
+			Rule:    f.Rule,
+			Limit:   f.Limit,
+			Message: p.Sprintf(f.Message, f.Args...),
+		})
+	}
+	return l
+}
+
+// ErrorHandler is an echo.HTTPErrorHandler that answers the errors handlers
+// return, and the ones echo raises itself, with a problem.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var p *Error
+	var he *echo.HTTPError
+	switch {
+	case errors.As(err, &p):
+	case errors.As(err, &he):
+		p = New(he.Code, Code(he.Code), http.StatusText(he.Code))
+		if m, ok := he.Message.(string); ok && he.Code < http.StatusInternalServerError {
+			p.Message = m
+		}
+	default:
+		logger.FromContext(c.Request().Context()).Error("unhandled error", slog.String("error", err.Error()))
+		p = New(http.StatusInternalServerError, CodeInternal, http.StatusText(http.StatusInternalServerError))
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(p.Status)
+	} else {
+		err = JSON(c, p)
+	}
+	if err != nil {
+		logger.FromContext(c.Request().Context()).Error("writing problem failed", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,154 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		lang     string
+		wantHttp int
+		want     Problem
+	}{
+		{
+			name:     "Problem returned by a handler",
+			method:   http.MethodPost,
+			path:     "/invalid",
+			lang:     "en-US,en;q=0.9",
+			wantHttp: http.StatusBadRequest,
+			want: Problem{
+				Type: "/problems/invalid_amount", Title: "Bad Request", Status: http.StatusBadRequest,
+				Detail: "Invalid donation amount", Instance: "/invalid", Code: "invalid_amount", Message: "Invalid donation amount",
+				Errors: []FieldError{{Pointer: "/rows/2/amount", Rule: "gte", Limit: "0", Message: "Invalid donation amount"}},
+			},
+		},
+		{
+			name:     "Problem in Thai",
+			method:   http.MethodPost,
+			path:     "/invalid",
+			lang:     "th-TH,en;q=0.8",
+			wantHttp: http.StatusBadRequest,
+			want: Problem{
+				Type: "/problems/invalid_amount", Title: "คำขอไม่ถูกต้อง", Status: http.StatusBadRequest,
+				Detail: "จำนวนเงิน donation ไม่ถูกต้อง", Instance: "/invalid", Code: "invalid_amount", Message: "Invalid donation amount",
+				Errors: []FieldError{{Pointer: "/rows/2/amount", Rule: "gte", Limit: "0", Message: "จำนวนเงิน donation ไม่ถูกต้อง"}},
+			},
+		},
+		{
+			name:     "Unknown route",
+			method:   http.MethodGet,
+			path:     "/missing",
+			lang:     "en",
+			wantHttp: http.StatusNotFound,
+			want: Problem{
+				Type: "/problems/not_found", Title: "Not Found", Status: http.StatusNotFound,
+				Detail: "Not Found", Instance: "/missing", Code: CodeNotFound, Message: "Not Found",
+			},
+		},
+		{
+			name:     "Plain error",
+			method:   http.MethodGet,
+			path:     "/broken",
+			lang:     "en",
+			wantHttp: http.StatusInternalServerError,
+			want: Problem{
+				Type: "/problems/internal_error", Title: "Internal Server Error", Status: http.StatusInternalServerError,
+				Detail: "Internal Server Error", Instance: "/broken", Code: CodeInternal, Message: "Internal Server Error",
+			},
+		},
+	}
+
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler
+	e.POST("/invalid", func(c echo.Context) error {
+		err := Invalid("invalid_amount", "Invalid %s amount", Field("/amount", "gte", "0", "Invalid %s amount", "donation")).With("donation")
+		return Nest(err, "/rows/2")
+	})
+	e.GET("/broken", func(c echo.Context) error {
+		return errors.New("connection reset by peer")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Accept-Language", tt.lang)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+
+			if got := rec.Header().Get(echo.HeaderContentType); got != MIMEProblemJSON {
+				t.Errorf("expected content type %s but got %s", MIMEProblemJSON, got)
+			}
+
+			var got Problem
+			err := json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Errorf("error unmarshalling json: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %+v but got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		namespace string
+		want      string
+	}{
+		{namespace: "TaxRequest.totalIncome", want: "/totalIncome"},
+		{namespace: "TaxRequest.allowances[1].allowanceType", want: "/allowances/1/allowanceType"},
+		{namespace: "HouseholdRequest.spouse.allowances[0].amount", want: "/spouse/allowances/0/amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			if got := pointer(tt.namespace); got != tt.want {
+				t.Errorf("expected %s but got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	err := Invalid("invalid_amount", "Invalid %s amount", Field("/amount", "gte", "0", "Invalid %s amount", "donation")).With("donation")
+	st := status.Convert(GRPC(err, i18n.NewPrinter(i18n.Thai)))
+
+	if st.Code() != grpccodes.InvalidArgument {
+		t.Errorf("expected code %s but got %s", grpccodes.InvalidArgument, st.Code())
+	}
+	if want := "จำนวนเงิน donation ไม่ถูกต้อง"; st.Message() != want {
+		t.Errorf("expected message %s but got %s", want, st.Message())
+	}
+
+	var reason, field string
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			reason = d.Reason
+		case *errdetails.BadRequest:
+			field = d.FieldViolations[0].Field
+		}
+	}
+	if reason != "invalid_amount" || field != "/amount" {
+		t.Errorf("expected reason invalid_amount at /amount but got %s at %s", reason, field)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	cfg "github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/health"
+	"github.com/Gitong23/assessment-tax/metrics"
+	"github.com/Gitong23/assessment-tax/openapi"
+	"github.com/Gitong23/assessment-tax/tax"
+	"github.com/labstack/echo/v4"
+)
+
+// api holds what the routes are served by.
+type api struct {
+	tax *tax.Handler
+	// approvals is nil when deduction changes apply without approval.
+	approvals *tax.ApprovalHandler
+	users     *auth.Handler
+	keys      *apikey.Handler
+	limiter   *apikey.Limiter
+	probes    *health.Handler
+	// admin authenticates the admin routes.
+	admin echo.MiddlewareFunc
+	spec  *openapi.Spec
+	// v1 announces the deprecation of the v1 calculation routes.
+	v1 cfg.Deprecation
+}
+
+// routes registers the routes of a. Every calculation and admin route is
+// documented by spec.
+func routes(e *echo.Echo, a api) {
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "Hello, Go Bootcamp!")
+	})
+	e.GET("/metrics", metrics.Handler())
+	e.GET("/healthz", a.probes.Live)
+	e.GET("/readyz", a.probes.Ready)
+	e.GET("/openapi.json", a.spec.JSON)
+	e.GET("/docs", a.spec.Docs)
+
+	// v1 is also served without a prefix, as it was before the
+	// calculations were versioned.
+	for _, prefix := range []string{"", "/v1"} {
+		e.POST(prefix+"/tax/calculations", a.tax.Tax, deprecated(a.v1, "/v2/tax/calculations"), a.limiter.Middleware)
+		e.POST(prefix+"/tax/calculations/upload-csv", a.tax.UploadCsv, deprecated(a.v1, "/v2/tax/calculations/upload-csv"), a.limiter.Middleware)
+		e.POST(prefix+"/tax/calculations/household", a.tax.Household, deprecated(a.v1, "/v2/tax/calculations/household"), a.limiter.Middleware)
+	}
+	e.POST("/v2/tax/calculations", a.tax.TaxV2, a.limiter.Middleware)
+	e.POST("/v2/tax/calculations/upload-csv", a.tax.UploadCsvV2, a.limiter.Middleware)
+	e.POST("/v2/tax/calculations/household", a.tax.HouseholdV2, a.limiter.Middleware)
+
+	g := e.Group("/admin", a.admin)
+	g.GET("/deductions", a.tax.Deductions, auth.Require(auth.RoleViewer))
+	if a.approvals != nil {
+		g.POST("/deductions/personal", a.approvals.SubmitPersonalDeduct, auth.Require(auth.RoleEditor))
+		g.POST("/deductions/k-receipt", a.approvals.SubmitKreceiptDeduct, auth.Require(auth.RoleEditor))
+		g.GET("/deductions/changes", a.approvals.Changes, auth.Require(auth.RoleViewer))
+		g.GET("/deductions/changes/:id", a.approvals.Change, auth.Require(auth.RoleViewer))
+		g.POST("/deductions/changes/:id/comments", a.approvals.Comment, auth.Require(auth.RoleEditor))
+		g.POST("/deductions/changes/:id/approve", a.approvals.Approve, auth.Require(auth.RoleApprover))
+		g.POST("/deductions/changes/:id/reject", a.approvals.Reject, auth.Require(auth.RoleApprover))
+	} else {
+		g.POST("/deductions/personal", a.tax.UpdateInitPersonalDeduct, auth.Require(auth.RoleEditor))
+		g.POST("/deductions/k-receipt", a.tax.UpdateMaxKreceiptDeduct, auth.Require(auth.RoleEditor))
+	}
+	g.POST("/exchange-rates/upload-csv", a.tax.UploadExchangeRateCsv, auth.Require(auth.RoleEditor))
+	g.POST("/samples/upload-csv", a.tax.UploadSampleCsv, auth.Require(auth.RoleEditor))
+
+	g.GET("/users", a.users.Users, auth.Require(auth.RoleApprover))
+	g.POST("/users", a.users.CreateUser, auth.Require(auth.RoleApprover))
+	g.DELETE("/users/:username", a.users.DeleteUser, auth.Require(auth.RoleApprover))
+
+	g.GET("/api-keys", a.keys.Keys, auth.Require(auth.RoleViewer))
+	g.POST("/api-keys", a.keys.CreateKey, auth.Require(auth.RoleEditor))
+	g.DELETE("/api-keys/:id", a.keys.RevokeKey, auth.Require(auth.RoleEditor))
+	g.GET("/api-keys/:id/usage", a.keys.KeyUsage, auth.Require(auth.RoleViewer))
+}
+
+// deprecated announces on every response of a route when it was deprecated,
+// when it stops being served and the route that replaces it.
+func deprecated(d cfg.Deprecation, successor string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			h := c.Response().Header()
+			h.Set("Deprecation", fmt.Sprintf("@%d", d.Date.Unix()))
+			h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			h.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, successor))
+			return next(c)
+		}
+	}
+}
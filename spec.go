@@ -0,0 +1,190 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	"github.com/Gitong23/assessment-tax/openapi"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+var (
+	public = []string{"apiKey"}
+	admin  = []string{"basicAuth", "bearerAuth"}
+
+	dryRun = openapi.Parameter{
+		Name:        "dryRun",
+		In:          "query",
+		Description: "Respond with the impact of the change on the sample population without making it",
+		Schema:      &openapi.Schema{Type: "boolean"},
+	}
+)
+
+// newSpec documents the calculation and admin routes.
+func newSpec() *openapi.Spec {
+	s := openapi.New(openapi.Info{
+		Title:       "K-Tax API",
+		Version:     "2.0.0",
+		Description: "Thai personal income tax calculations and the admin settings they use.",
+	}, problem.Problem{}, problem.MIMEProblemJSON)
+
+	s.SecurityScheme("apiKey", openapi.SecurityScheme{Type: "apiKey", Name: apikey.HeaderAPIKey, In: "header"})
+	s.SecurityScheme("basicAuth", openapi.SecurityScheme{Type: "http", Scheme: "basic"})
+	s.SecurityScheme("bearerAuth", openapi.SecurityScheme{Type: "http", Scheme: "bearer"})
+
+	// v1 is also served without a prefix.
+	for _, prefix := range []string{"", "/v1"} {
+		s.Add(openapi.Op{
+			Method: http.MethodPost, Path: prefix + "/tax/calculations", Tags: []string{"tax"}, Security: public,
+			Summary:    "Calculate the tax of a filer",
+			Request:    tax.TaxRequest{},
+			Responses:  []openapi.Resp{{Status: http.StatusOK, Description: "Tax payable or refund", Body: tax.TaxResponse{}}},
+			Deprecated: true,
+		})
+		s.Add(openapi.Op{
+			Method: http.MethodPost, Path: prefix + "/tax/calculations/upload-csv", Tags: []string{"tax"}, Security: public,
+			Summary:    "Calculate the tax of every row of CSV files",
+			Files:      []string{"taxFile"},
+			Responses:  []openapi.Resp{{Status: http.StatusOK, Description: "Tax of every row", Body: tax.TaxUploadResponse{}}},
+			Deprecated: true,
+		})
+		s.Add(openapi.Op{
+			Method: http.MethodPost, Path: prefix + "/tax/calculations/household", Tags: []string{"tax"}, Security: public,
+			Summary:    "Compare filing separately and jointly for spouses",
+			Request:    tax.HouseholdRequest{},
+			Responses:  []openapi.Resp{{Status: http.StatusOK, Description: "Filing options", Body: tax.HouseholdResponse{}}},
+			Deprecated: true,
+		})
+	}
+
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/v2/tax/calculations", Tags: []string{"tax"}, Security: public,
+		Summary:   "Calculate the tax of a filer for a tax year",
+		Request:   tax.TaxRequestV2{},
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Tax payable and refund with how they were reached", Body: tax.TaxResponseV2{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/v2/tax/calculations/upload-csv", Tags: []string{"tax"}, Security: public,
+		Summary:   "Calculate the tax of every row of CSV files for the taxYear form field",
+		Files:     []string{"taxFile"},
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Tax of every row", Body: tax.TaxUploadResponseV2{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/v2/tax/calculations/household", Tags: []string{"tax"}, Security: public,
+		Summary:   "Compare filing separately and jointly for spouses for a tax year",
+		Request:   tax.HouseholdRequestV2{},
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Filing options", Body: tax.HouseholdResponseV2{}}},
+	})
+
+	s.Add(openapi.Op{
+		Method: http.MethodGet, Path: "/admin/deductions", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "List the allowances",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Allowances", Body: []tax.Allowances{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/deductions/personal", Tags: []string{"deductions"}, Security: admin,
+		Summary: "Set the personal deduction",
+		Query:   []openapi.Parameter{dryRun},
+		Request: tax.DeductionReq{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Deduction set, or its impact on a dry run", Body: tax.InitPersonalDeductRes{}},
+			{Status: http.StatusOK, Body: tax.Impact{}},
+			{Status: http.StatusAccepted, Description: "Change submitted for approval", Body: tax.DeductionChange{}},
+		},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/deductions/k-receipt", Tags: []string{"deductions"}, Security: admin,
+		Summary: "Set the maximum k-receipt deduction",
+		Query:   []openapi.Parameter{dryRun},
+		Request: tax.DeductionReq{},
+		Responses: []openapi.Resp{
+			{Status: http.StatusOK, Description: "Deduction set, or its impact on a dry run", Body: tax.MaxKreceiptRes{}},
+			{Status: http.StatusOK, Body: tax.Impact{}},
+			{Status: http.StatusAccepted, Description: "Change submitted for approval", Body: tax.DeductionChange{}},
+		},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodGet, Path: "/admin/deductions/changes", Tags: []string{"deductions"}, Security: admin,
+		Summary: "List the deduction changes",
+		Query: []openapi.Parameter{{
+			Name: "status", In: "query",
+			Schema: &openapi.Schema{Type: "string", Enum: []string{tax.ChangePending, tax.ChangeApproved, tax.ChangeRejected}},
+		}},
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Deduction changes", Body: []tax.DeductionChange{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodGet, Path: "/admin/deductions/changes/:id", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "Get a deduction change and its comments",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Deduction change", Body: tax.DeductionChange{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/deductions/changes/:id/comments", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "Comment on a deduction change",
+		Request:   tax.CommentReq{},
+		Responses: []openapi.Resp{{Status: http.StatusCreated, Description: "Comment added", Body: tax.ChangeComment{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/deductions/changes/:id/approve", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "Approve and apply a deduction change",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Approved change", Body: tax.DeductionChange{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/deductions/changes/:id/reject", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "Reject a deduction change",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Rejected change", Body: tax.DeductionChange{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/exchange-rates/upload-csv", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "Upload exchange rates from CSV files",
+		Files:     []string{"rateFile"},
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Rates stored", Body: tax.ExchangeRateUploadResponse{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/samples/upload-csv", Tags: []string{"deductions"}, Security: admin,
+		Summary:   "Replace the sample population dry runs are measured on",
+		Files:     []string{"taxFile"},
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Samples stored", Body: tax.SampleUploadResponse{}}},
+	})
+
+	s.Add(openapi.Op{
+		Method: http.MethodGet, Path: "/admin/users", Tags: []string{"users"}, Security: admin,
+		Summary:   "List the admin users",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Admin users", Body: []auth.User{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/users", Tags: []string{"users"}, Security: admin,
+		Summary:   "Create an admin user",
+		Request:   auth.UserReq{},
+		Responses: []openapi.Resp{{Status: http.StatusCreated, Description: "Admin user created", Body: auth.User{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodDelete, Path: "/admin/users/:username", Tags: []string{"users"}, Security: admin,
+		Summary:   "Delete an admin user",
+		Responses: []openapi.Resp{{Status: http.StatusNoContent, Description: "Admin user deleted"}},
+	})
+
+	s.Add(openapi.Op{
+		Method: http.MethodGet, Path: "/admin/api-keys", Tags: []string{"api-keys"}, Security: admin,
+		Summary:   "List the API keys",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "API keys", Body: []apikey.Key{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodPost, Path: "/admin/api-keys", Tags: []string{"api-keys"}, Security: admin,
+		Summary:   "Create an API key",
+		Request:   apikey.KeyReq{},
+		Responses: []openapi.Resp{{Status: http.StatusCreated, Description: "API key created, shown only this once", Body: apikey.KeyRes{}}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodDelete, Path: "/admin/api-keys/:id", Tags: []string{"api-keys"}, Security: admin,
+		Summary:   "Revoke an API key",
+		Responses: []openapi.Resp{{Status: http.StatusNoContent, Description: "API key revoked"}},
+	})
+	s.Add(openapi.Op{
+		Method: http.MethodGet, Path: "/admin/api-keys/:id/usage", Tags: []string{"api-keys"}, Security: admin,
+		Summary:   "Get the daily usage of an API key",
+		Responses: []openapi.Resp{{Status: http.StatusOK, Description: "Daily usage", Body: []apikey.Usage{}}},
+	})
+	return s
+}
@@ -0,0 +1,119 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func (s *SQLite) PersonalAllowance(ctx context.Context) (_ *tax.Allowances, err error) {
+	ctx, done := s.bound(ctx, "PersonalAllowance", &err)
+	defer done()
+
+	row, err := s.Db.QueryContext(ctx, "SELECT * FROM allowances WHERE type = 'personal'")
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+
+	var personal tax.Allowances
+	for row.Next() {
+		err := row.Scan(
+			&personal.ID,
+			&personal.Type,
+			&personal.InitAmount,
+			&personal.MinAmount,
+			&personal.MaxAmount,
+			&personal.LimitMaxAmount,
+			&personal.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &personal, row.Err()
+}
+
+func (s *SQLite) DonationAllowance(ctx context.Context) (_ *tax.Allowances, err error) {
+	ctx, done := s.bound(ctx, "DonationAllowance", &err)
+	defer done()
+
+	row, err := s.Db.QueryContext(ctx, "SELECT * FROM allowances WHERE type = 'donation'")
+
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+
+	var d tax.Allowances
+	for row.Next() {
+		err := row.Scan(
+			&d.ID,
+			&d.Type,
+			&d.InitAmount,
+			&d.MinAmount,
+			&d.MaxAmount,
+			&d.LimitMaxAmount,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &d, row.Err()
+}
+
+func (s *SQLite) KreceiptAllowance(ctx context.Context) (_ *tax.Allowances, err error) {
+	ctx, done := s.bound(ctx, "KreceiptAllowance", &err)
+	defer done()
+
+	row, err := s.Db.QueryContext(ctx, "SELECT * FROM allowances WHERE type = 'k-receipt'")
+
+	if err != nil {
+		return nil, err
+	}
+	defer row.Close()
+
+	var k tax.Allowances
+	for row.Next() {
+		err := row.Scan(
+			&k.ID,
+			&k.Type,
+			&k.InitAmount,
+			&k.MinAmount,
+			&k.MaxAmount,
+			&k.LimitMaxAmount,
+			&k.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &k, row.Err()
+}
+
+func (s *SQLite) UpdateInitPersonalAllowance(ctx context.Context, amount float64) (_ *tax.Allowances, err error) {
+	boundCtx, done := s.bound(ctx, "UpdateInitPersonalAllowance", &err)
+	defer done()
+
+	_, err = s.Db.ExecContext(boundCtx, "UPDATE allowances SET init_amount = ?1 WHERE type = 'personal'", amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.PersonalAllowance(ctx)
+}
+
+func (s *SQLite) UpdateMaxAmountKreceipt(ctx context.Context, amount float64) (_ *tax.Allowances, err error) {
+	boundCtx, done := s.bound(ctx, "UpdateMaxAmountKreceipt", &err)
+	defer done()
+
+	_, err = s.Db.ExecContext(boundCtx, "UPDATE allowances SET max_amount = ?1 WHERE type = 'k-receipt'", amount)
+	if err != nil {
+		return nil, err
+	}
+	return s.KreceiptAllowance(ctx)
+}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+)
+
+const apiKeyColumns = "id, name, prefix, key_hash, requests_per_minute, rows_per_day, created_at, revoked_at"
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (*apikey.Key, error) {
+	var k apikey.Key
+	var revokedAt sql.NullString
+	err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.Hash, &k.RequestsPerMinute, &k.RowsPerDay, &k.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.String
+	}
+	return &k, nil
+}
+
+func (s *SQLite) APIKey(ctx context.Context, hash string) (_ *apikey.Key, err error) {
+	ctx, done := s.bound(ctx, "APIKey", &err)
+	defer done()
+
+	k, err := scanAPIKey(s.Db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE key_hash = ?1", hash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return k, err
+}
+
+func (s *SQLite) APIKeys(ctx context.Context) (_ []apikey.Key, err error) {
+	ctx, done := s.bound(ctx, "APIKeys", &err)
+	defer done()
+
+	rows, err := s.Db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []apikey.Key{}
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+
+	return keys, rows.Err()
+}
+
+func (s *SQLite) CreateAPIKey(ctx context.Context, k apikey.Key) (_ *apikey.Key, err error) {
+	ctx, done := s.bound(ctx, "CreateAPIKey", &err)
+	defer done()
+
+	row := s.Db.QueryRowContext(ctx, `INSERT INTO api_keys (name, prefix, key_hash, requests_per_minute, rows_per_day)
+		VALUES (?1, ?2, ?3, ?4, ?5) RETURNING `+apiKeyColumns, k.Name, k.Prefix, k.Hash, k.RequestsPerMinute, k.RowsPerDay)
+	return scanAPIKey(row)
+}
+
+func (s *SQLite) RevokeAPIKey(ctx context.Context, id int) (err error) {
+	ctx, done := s.bound(ctx, "RevokeAPIKey", &err)
+	defer done()
+
+	_, err = s.Db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = strftime('%Y-%m-%dT%H:%M:%SZ', 'now') WHERE id = ?1 AND revoked_at IS NULL", id)
+	return err
+}
+
+func (s *SQLite) RecordUsage(ctx context.Context, id int, date string, requests int, rows int) (_ *apikey.Usage, err error) {
+	ctx, done := s.bound(ctx, "RecordUsage", &err)
+	defer done()
+
+	row := s.Db.QueryRowContext(ctx, `INSERT INTO api_key_usage (api_key_id, date, requests, csv_rows) VALUES (?1, ?2, ?3, ?4)
+		ON CONFLICT (api_key_id, date) DO UPDATE SET
+			requests = api_key_usage.requests + EXCLUDED.requests,
+			csv_rows = api_key_usage.csv_rows + EXCLUDED.csv_rows
+		RETURNING date, requests, csv_rows`, id, date, requests, rows)
+
+	var u apikey.Usage
+	err = row.Scan(&u.Date, &u.Requests, &u.Rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+func (s *SQLite) Usage(ctx context.Context, id int) (_ []apikey.Usage, err error) {
+	ctx, done := s.bound(ctx, "Usage", &err)
+	defer done()
+
+	rows, err := s.Db.QueryContext(ctx, "SELECT date, requests, csv_rows FROM api_key_usage WHERE api_key_id = ?1 ORDER BY date DESC", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := []apikey.Usage{}
+	for rows.Next() {
+		var u apikey.Usage
+		err := rows.Scan(&u.Date, &u.Requests, &u.Rows)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+func (s *SQLite) ExchangeRate(ctx context.Context, currency string, date string) (_ *tax.ExchangeRate, err error) {
+	ctx, done := s.bound(ctx, "ExchangeRate", &err)
+	defer done()
+
+	row := s.Db.QueryRowContext(ctx, `SELECT date, currency, rate FROM exchange_rates
+		WHERE currency = ?1 AND date <= ?2 AND date > date(?2, ?3) ORDER BY date DESC LIMIT 1`, currency, date, fmt.Sprintf("-%d days", tax.RateWindow))
+
+	var r tax.ExchangeRate
+	err = row.Scan(&r.Date, &r.Currency, &r.Rate)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (s *SQLite) UpdateExchangeRates(ctx context.Context, rates []tax.ExchangeRate) (_ []tax.ExchangeRate, err error) {
+	ctx, done := s.bound(ctx, "UpdateExchangeRates", &err)
+	defer done()
+
+	tx, err := s.Db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, r := range rates {
+		_, err := tx.ExecContext(ctx, `INSERT INTO exchange_rates (date, currency, rate) VALUES (?1, ?2, ?3)
+			ON CONFLICT (date, currency) DO UPDATE SET rate = EXCLUDED.rate`, r.Date, r.Currency, r.Rate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
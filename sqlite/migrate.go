@@ -0,0 +1,196 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/migrations"
+)
+
+func (s *SQLite) createMigrationsTable() error {
+	_, err := s.Db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+	)`)
+	return err
+}
+
+func (s *SQLite) appliedMigrations(ctx context.Context) (map[int]string, error) {
+	rows, err := s.Db.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]string{}
+	for rows.Next() {
+		var version int
+		var at string
+		err := rows.Scan(&version, &at)
+		if err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// apply runs m in a transaction, which holds the write lock of the file from
+// its start, so processes sharing the file don't apply it twice.
+func (s *SQLite) apply(m migrations.Migration, up bool) error {
+	tx, err := s.Db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var applied bool
+	err = tx.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = ?)", m.Version).Scan(&applied)
+	if err != nil {
+		return err
+	}
+
+	// Another process got here first.
+	if applied == up {
+		return nil
+	}
+
+	if up {
+		_, err = tx.Exec(m.Up)
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		_, err = tx.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name)
+		if err != nil {
+			return err
+		}
+	} else {
+		_, err = tx.Exec(m.Down)
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", m.Version, m.Name, err)
+		}
+
+		_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Migrate applies every migration that hasn't been applied yet.
+func (s *SQLite) Migrate() error {
+	all, err := migrations.Load(migrations.SQLite)
+	if err != nil {
+		return err
+	}
+
+	err = s.createMigrationsTable()
+	if err != nil {
+		return err
+	}
+
+	applied, err := s.appliedMigrations(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, m := range all {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+
+		err := s.apply(m, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback reverts the last steps applied migrations.
+func (s *SQLite) Rollback(steps int) error {
+	all, err := migrations.Load(migrations.SQLite)
+	if err != nil {
+		return err
+	}
+
+	err = s.createMigrationsTable()
+	if err != nil {
+		return err
+	}
+
+	applied, err := s.appliedMigrations(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for i := len(all) - 1; i >= 0 && steps > 0; i-- {
+		if _, ok := applied[all[i].Version]; !ok {
+			continue
+		}
+
+		err := s.apply(all[i], false)
+		if err != nil {
+			return err
+		}
+		steps--
+	}
+	return nil
+}
+
+// PendingMigrations returns how many migrations haven't been applied yet
+// without changing the schema.
+func (s *SQLite) PendingMigrations(ctx context.Context) (_ int, err error) {
+	ctx, done := s.bound(ctx, "PendingMigrations", &err)
+	defer done()
+
+	all, err := migrations.Load(migrations.SQLite)
+	if err != nil {
+		return 0, err
+	}
+
+	var exists bool
+	err = s.Db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')").Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return len(all), nil
+	}
+
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, m := range all {
+		if _, ok := applied[m.Version]; !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+func (s *SQLite) MigrationStatus() ([]migrations.Status, error) {
+	all, err := migrations.Load(migrations.SQLite)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.createMigrationsTable()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := s.appliedMigrations(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return migrations.Statuses(all, applied), nil
+}
@@ -0,0 +1,136 @@
+// Package sqlite keeps everything the server stores in a SQLite file, for
+// offices that run the service as a single binary without Postgres. Its
+// schema is the one the postgres package migrates to, in the SQLite dialect.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/metrics"
+	"github.com/Gitong23/assessment-tax/tracing"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+type SQLite struct {
+	Db      *sql.DB
+	timeout time.Duration
+}
+
+// dsn adds the pragmas every connection needs to url, the path of the
+// database file. Writers wait for each other for up to timeout instead of
+// failing, and transactions take the write lock when they begin so two of
+// them can't deadlock upgrading theirs.
+func dsn(url string, timeout time.Duration) string {
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%s_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)&_txlock=immediate",
+		url, sep, timeout.Milliseconds())
+}
+
+// Open opens the database file of cfg.Url, creating it when it's missing,
+// without touching its schema.
+func Open(cfg config.DB) (*SQLite, error) {
+	if cfg.Url == "" {
+		return nil, errors.New("DATABASE_URL must be the path of the SQLite file")
+	}
+
+	db, err := sql.Open("sqlite", dsn(cfg.Url, cfg.QueryTimeout))
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	ctx, cancel := context.Background(), func() {}
+	if cfg.ConnectTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cfg.ConnectTimeout)
+	}
+	defer cancel()
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	return &SQLite{Db: db, timeout: cfg.QueryTimeout}, nil
+}
+
+// Ping checks the database answers within the query timeout.
+func (s *SQLite) Ping(ctx context.Context) (err error) {
+	ctx, done := s.bound(ctx, "Ping", &err)
+	defer done()
+
+	return s.Db.PingContext(ctx)
+}
+
+func New() (*SQLite, error) {
+	cfg := config.New().DB
+
+	s, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.AutoMigrate {
+		err = s.Migrate()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// unavailable reports whether err means the database was locked by another
+// writer for longer than the timeout or didn't answer in time, rather than
+// that the query failed.
+func unavailable(err error) bool {
+	if err == nil || errors.Is(err, helper.ErrStoreUnavailable) {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) {
+		return true
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		// Extended result codes keep the primary code in the low byte.
+		code := sqliteErr.Code() & 0xff
+		return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+	}
+	return false
+}
+
+// bound starts a span for the store method and limits ctx to the
+// configured query timeout. The returned func releases ctx, observes how
+// long the method took, wraps *err in helper.ErrStoreUnavailable when the
+// database couldn't be used and ends the span.
+func (s *SQLite) bound(ctx context.Context, method string, err *error) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "sqlite."+method, semconv.DBSystemSqlite)
+	cancel := func() {}
+	if s.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+	}
+
+	return ctx, func() {
+		cancel()
+		metrics.DBQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		if unavailable(*err) {
+			*err = fmt.Errorf("%w: %v", helper.ErrStoreUnavailable, *err)
+		}
+		tracing.End(span, *err)
+	}
+}
@@ -0,0 +1,120 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/migrations"
+	"github.com/Gitong23/assessment-tax/storetest"
+)
+
+func open(t *testing.T) *SQLite {
+	s, err := Open(config.DB{Url: filepath.Join(t.TempDir(), "ktax.db"), ConnectTimeout: 5 * time.Second, QueryTimeout: 5 * time.Second, MaxOpenConns: 5, MaxIdleConns: 5})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	t.Cleanup(func() { s.Db.Close() })
+	return s
+}
+
+func TestUnavailable(t *testing.T) {
+	queryErr := open(t).Db.QueryRow("SELECT x FROM missing").Scan(new(int))
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "No error", err: nil, want: false},
+		{name: "Query timeout", err: context.DeadlineExceeded, want: true},
+		{name: "Wrapped query timeout", err: fmt.Errorf("scan: %w", context.DeadlineExceeded), want: true},
+		{name: "Client gone", err: context.Canceled, want: false},
+		{name: "No such table", err: queryErr, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unavailable(tt.err)
+			if got != tt.want {
+				t.Errorf("expected %v but got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUnavailableLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ktax.db")
+	s, err := Open(config.DB{Url: path, ConnectTimeout: time.Second, QueryTimeout: 100 * time.Millisecond, MaxOpenConns: 2})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	defer s.Db.Close()
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("expected no error migrating the database but got %v", err)
+	}
+
+	// Hold the write lock from another transaction.
+	tx, err := s.Db.Begin()
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	defer tx.Rollback()
+
+	_, err = s.UpdateInitPersonalAllowance(context.Background(), 70000)
+	if !errors.Is(err, helper.ErrStoreUnavailable) {
+		t.Errorf("expected the store to be unavailable but got %v", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	s := open(t)
+	all, err := migrations.Load(migrations.SQLite)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	pending, err := s.PendingMigrations(context.Background())
+	if err != nil || pending != len(all) {
+		t.Fatalf("expected %d pending migrations but got %d, %v", len(all), pending, err)
+	}
+
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("expected no error migrating the database but got %v", err)
+	}
+	if err := s.Migrate(); err != nil {
+		t.Fatalf("expected migrating twice to do nothing but got %v", err)
+	}
+	pending, err = s.PendingMigrations(context.Background())
+	if err != nil || pending != 0 {
+		t.Fatalf("expected no pending migrations but got %d, %v", pending, err)
+	}
+
+	if err := s.Rollback(len(all)); err != nil {
+		t.Fatalf("expected no error rolling back but got %v", err)
+	}
+	status, err := s.MigrationStatus()
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	for _, m := range status {
+		if m.Applied {
+			t.Errorf("expected migration %d_%s to be rolled back", m.Version, m.Name)
+		}
+	}
+}
+
+func TestContract(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		s := open(t)
+		if err := s.Migrate(); err != nil {
+			t.Fatalf("expected no error migrating the database but got %v", err)
+		}
+		return s
+	})
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	cfg "github.com/Gitong23/assessment-tax/config"
+	"github.com/Gitong23/assessment-tax/health"
+	"github.com/Gitong23/assessment-tax/memory"
+	"github.com/Gitong23/assessment-tax/postgres"
+	"github.com/Gitong23/assessment-tax/sqlite"
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+// store is everything the server keeps.
+type store interface {
+	tax.Storer
+	tax.ChangeStorer
+	auth.Storer
+	apikey.Storer
+}
+
+// database is a store kept in a migrated SQL database.
+type database interface {
+	store
+	Ping(ctx context.Context) error
+	PendingMigrations(ctx context.Context) (int, error)
+}
+
+// databaseChecks are the checks that db answers and is fully migrated.
+func databaseChecks(db database) []health.Check {
+	return []health.Check{
+		{Name: "database", Run: db.Ping},
+		{Name: "migrations", Run: func(ctx context.Context) error {
+			pending, err := db.PendingMigrations(ctx)
+			if err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%d pending migrations", pending)
+			}
+			return nil
+		}},
+	}
+}
+
+// openStore opens the store of STORE_DRIVER with the checks that it's
+// ready.
+func openStore(config *cfg.Config) (store, []health.Check, error) {
+	switch config.Store.Driver {
+	case cfg.StorePostgres:
+		p, err := postgres.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		return p, databaseChecks(p), nil
+	case cfg.StoreSQLite:
+		s, err := sqlite.New()
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, databaseChecks(s), nil
+	case cfg.StoreMemory:
+		return memory.New(), nil, nil
+	case cfg.StoreFile:
+		s, err := memory.Open(config.Store.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, nil, nil
+	}
+	return nil, nil, fmt.Errorf("unknown STORE_DRIVER %q", config.Store.Driver)
+}
@@ -0,0 +1,405 @@
+// Package storetest checks that a store keeps and answers what the
+// handlers expect of it, so every driver behaves like the database.
+package storetest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	"github.com/Gitong23/assessment-tax/tax"
+)
+
+// Store is everything the server keeps.
+type Store interface {
+	tax.Storer
+	tax.ChangeStorer
+	auth.Storer
+	apikey.Storer
+}
+
+// Run runs the contract against stores from open, which must be fresh and
+// seeded with the allowances the migrations seed.
+func Run(t *testing.T, open func(t *testing.T) Store) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s Store)
+	}{
+		{name: "Allowances", run: allowances},
+		{name: "Exchange rates", run: exchangeRates},
+		{name: "Sample tax requests", run: samples},
+		{name: "Deduction changes", run: deductionChanges},
+		{name: "Admin users", run: adminUsers},
+		{name: "API keys", run: apiKeys},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, open(t))
+		})
+	}
+}
+
+// amounts leaves out the ID and creation time the store sets.
+func amounts(a *tax.Allowances) tax.Allowances {
+	return tax.Allowances{Type: a.Type, InitAmount: a.InitAmount, MinAmount: a.MinAmount, MaxAmount: a.MaxAmount, LimitMaxAmount: a.LimitMaxAmount}
+}
+
+func allowance(t *testing.T, s Store, typ string) *tax.Allowances {
+	t.Helper()
+	get := map[string]func(context.Context) (*tax.Allowances, error){
+		"personal":  s.PersonalAllowance,
+		"donation":  s.DonationAllowance,
+		"k-receipt": s.KreceiptAllowance,
+	}
+
+	a, err := get[typ](context.Background())
+	if err != nil {
+		t.Fatalf("expected no error reading the %s allowance but got %v", typ, err)
+	}
+	return a
+}
+
+func allowances(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	for _, want := range tax.DefaultAllowances() {
+		got := allowance(t, s, want.Type)
+		if amounts(got) != amounts(&want) {
+			t.Errorf("expected the seeded %s allowance %+v but got %+v", want.Type, amounts(&want), amounts(got))
+		}
+	}
+
+	personal, err := s.UpdateInitPersonalAllowance(ctx, 70000)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if personal.InitAmount != 70000 || personal.MaxAmount != 100000 {
+		t.Errorf("expected personal to start at 70000 up to 100000 but got %+v", personal)
+	}
+	if got := allowance(t, s, "personal"); got.InitAmount != 70000 {
+		t.Errorf("expected personal to stay 70000 but got %v", got.InitAmount)
+	}
+
+	kReceipt, err := s.UpdateMaxAmountKreceipt(ctx, 80000)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if kReceipt.MaxAmount != 80000 || kReceipt.InitAmount != 0 {
+		t.Errorf("expected k-receipt to go up to 80000 but got %+v", kReceipt)
+	}
+	if got := allowance(t, s, "k-receipt"); got.MaxAmount != 80000 {
+		t.Errorf("expected k-receipt to stay 80000 but got %v", got.MaxAmount)
+	}
+}
+
+func exchangeRates(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	rate := func(currency, date string) *tax.ExchangeRate {
+		t.Helper()
+		r, err := s.ExchangeRate(ctx, currency, date)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		return r
+	}
+
+	if r := rate("USD", "2024-01-15"); r != nil {
+		t.Errorf("expected no rate before any is stored but got %+v", r)
+	}
+
+	_, err := s.UpdateExchangeRates(ctx, []tax.ExchangeRate{
+		{Date: "2024-01-01", Currency: "USD", Rate: 35},
+		{Date: "2024-02-01", Currency: "USD", Rate: 36},
+		{Date: "2024-01-01", Currency: "EUR", Rate: 38},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	tests := []struct {
+		currency string
+		date     string
+		want     *tax.ExchangeRate
+	}{
+		{currency: "USD", date: "2024-01-01", want: &tax.ExchangeRate{Date: "2024-01-01", Currency: "USD", Rate: 35}},
+		{currency: "USD", date: "2024-01-07", want: &tax.ExchangeRate{Date: "2024-01-01", Currency: "USD", Rate: 35}},
+		{currency: "USD", date: "2024-02-05", want: &tax.ExchangeRate{Date: "2024-02-01", Currency: "USD", Rate: 36}},
+		{currency: "EUR", date: "2024-01-03", want: &tax.ExchangeRate{Date: "2024-01-01", Currency: "EUR", Rate: 38}},
+		{currency: "USD", date: "2024-01-08"},
+		{currency: "EUR", date: "2024-03-01"},
+		{currency: "USD", date: "2023-12-31"},
+		{currency: "JPY", date: "2024-03-01"},
+	}
+	for _, tt := range tests {
+		if got := rate(tt.currency, tt.date); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("expected the %s rate on %s to be %+v but got %+v", tt.currency, tt.date, tt.want, got)
+		}
+	}
+
+	_, err = s.UpdateExchangeRates(ctx, []tax.ExchangeRate{{Date: "2024-01-01", Currency: "USD", Rate: 34.5}})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if got := rate("USD", "2024-01-05"); got == nil || got.Rate != 34.5 {
+		t.Errorf("expected the rate of 2024-01-01 to be replaced with 34.5 but got %+v", got)
+	}
+}
+
+func samples(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	got, err := s.SampleTaxRequests(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no samples but got %v", got)
+	}
+
+	err = s.UpdateSampleTaxRequests(ctx, []tax.TaxRequest{
+		{TotalIncome: 500000, WHT: 1000, Allowances: []tax.AllowanceReq{
+			{AllowanceType: "donation", Amount: 1000},
+			{AllowanceType: "donation", Amount: 2000},
+			{AllowanceType: "k-receipt", Amount: 3000},
+		}},
+		{TotalIncome: 800000},
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	want := []tax.TaxRequest{
+		{TotalIncome: 500000, WHT: 1000, Allowances: []tax.AllowanceReq{
+			{AllowanceType: "donation", Amount: 3000},
+			{AllowanceType: "k-receipt", Amount: 3000},
+		}},
+		{TotalIncome: 800000, Allowances: []tax.AllowanceReq{
+			{AllowanceType: "donation", Amount: 0},
+			{AllowanceType: "k-receipt", Amount: 0},
+		}},
+	}
+	got, err = s.SampleTaxRequests(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected samples %+v but got %+v", want, got)
+	}
+
+	err = s.UpdateSampleTaxRequests(ctx, want[1:])
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	got, err = s.SampleTaxRequests(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("expected the samples to be replaced with 1 but got %d", len(got))
+	}
+}
+
+func deductionChanges(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	personal, err := s.CreateDeductionChange(ctx, tax.DeductionChange{Type: "personal", Amount: 75000, Status: tax.ChangePending, SubmittedBy: "editor"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if personal.ID == 0 || personal.CreatedAt == "" || personal.Status != tax.ChangePending || personal.SubmittedBy != "editor" {
+		t.Errorf("expected a pending change with an ID and creation time but got %+v", personal)
+	}
+
+	kReceipt, err := s.CreateDeductionChange(ctx, tax.DeductionChange{Type: "k-receipt", Amount: 90000, Status: tax.ChangePending, SubmittedBy: "editor"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if kReceipt.ID == personal.ID {
+		t.Errorf("expected changes to get their own IDs but both got %d", kReceipt.ID)
+	}
+
+	list := func(status string) []int {
+		t.Helper()
+		changes, err := s.DeductionChanges(ctx, status)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		ids := []int{}
+		for _, c := range changes {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+	if got, want := list(""), []int{kReceipt.ID, personal.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected all changes newest first %v but got %v", want, got)
+	}
+
+	missing, err := s.DeductionChange(ctx, kReceipt.ID+100)
+	if err != nil || missing != nil {
+		t.Errorf("expected no change and no error but got %+v and %v", missing, err)
+	}
+
+	comment, err := s.AddDeductionChangeComment(ctx, personal.ID, "approver", "Looks right")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if comment.ID == 0 || comment.Author != "approver" || comment.Comment != "Looks right" || comment.CreatedAt == "" {
+		t.Errorf("expected the comment with an ID and creation time but got %+v", comment)
+	}
+
+	got, err := s.DeductionChange(ctx, personal.ID)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(got.Comments) != 1 || got.Comments[0] != *comment {
+		t.Errorf("expected the change to have the comment %+v but got %+v", comment, got.Comments)
+	}
+
+	approved, err := s.DecideDeductionChange(ctx, personal.ID, tax.ChangeApproved, "approver")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if approved.Status != tax.ChangeApproved || approved.DecidedBy != "approver" || approved.DecidedAt == "" || len(approved.Comments) != 1 {
+		t.Errorf("expected the change approved by approver with its comment but got %+v", approved)
+	}
+	if a := allowance(t, s, "personal"); a.InitAmount != 75000 {
+		t.Errorf("expected the approved change to set personal to 75000 but got %v", a.InitAmount)
+	}
+
+	again, err := s.DecideDeductionChange(ctx, personal.ID, tax.ChangeRejected, "approver")
+	if err != nil || again != nil {
+		t.Errorf("expected a decided change not to be decided again but got %+v and %v", again, err)
+	}
+
+	rejected, err := s.DecideDeductionChange(ctx, kReceipt.ID, tax.ChangeRejected, "approver")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if rejected.Status != tax.ChangeRejected {
+		t.Errorf("expected the change to be rejected but got %s", rejected.Status)
+	}
+	if a := allowance(t, s, "k-receipt"); a.MaxAmount != 50000 {
+		t.Errorf("expected the rejected change to leave k-receipt at 50000 but got %v", a.MaxAmount)
+	}
+
+	if got, want := list(tax.ChangeApproved), []int{personal.ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the approved changes %v but got %v", want, got)
+	}
+	if got := list(tax.ChangePending); len(got) != 0 {
+		t.Errorf("expected no pending changes but got %v", got)
+	}
+}
+
+func adminUsers(t *testing.T, s Store) {
+	ctx := context.Background()
+
+	missing, err := s.AdminUser(ctx, "editor")
+	if err != nil || missing != nil {
+		t.Errorf("expected no user and no error but got %+v and %v", missing, err)
+	}
+
+	created, err := s.CreateAdminUser(ctx, auth.User{Username: "editor", Role: "editor", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if created.ID == 0 || created.CreatedAt == "" {
+		t.Errorf("expected the user with an ID and creation time but got %+v", created)
+	}
+
+	_, err = s.CreateAdminUser(ctx, auth.User{Username: "approver", Role: "approver", PasswordHash: "hash"})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	_, err = s.CreateAdminUser(ctx, auth.User{Username: "editor", Role: "viewer", PasswordHash: "hash"})
+	if err == nil {
+		t.Errorf("expected an error creating a user with a taken username")
+	}
+
+	got, err := s.AdminUser(ctx, "editor")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if got == nil || got.ID != created.ID || got.Role != "editor" || got.PasswordHash != "hash" {
+		t.Errorf("expected the user %+v but got %+v", created, got)
+	}
+
+	err = s.DeleteAdminUser(ctx, "editor")
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	err = s.DeleteAdminUser(ctx, "editor")
+	if err != nil {
+		t.Errorf("expected no error deleting a missing user but got %v", err)
+	}
+
+	users, err := s.AdminUsers(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(users) != 1 || users[0].Username != "approver" {
+		t.Errorf("expected only approver to be left but got %+v", users)
+	}
+}
+
+func apiKeys(t *testing.T, s Store) {
+	ctx := context.Background()
+	hash := "8a1f0b6c3d2e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a"
+
+	missing, err := s.APIKey(ctx, hash)
+	if err != nil || missing != nil {
+		t.Errorf("expected no key and no error but got %+v and %v", missing, err)
+	}
+
+	created, err := s.CreateAPIKey(ctx, apikey.Key{Name: "Branch", Prefix: "ktax_8a1f", Hash: hash, RequestsPerMinute: 60, RowsPerDay: 1000})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if created.ID == 0 || created.CreatedAt == "" || created.RevokedAt != nil || created.RowsPerDay != 1000 {
+		t.Errorf("expected an active key with an ID and creation time but got %+v", created)
+	}
+
+	got, err := s.APIKey(ctx, hash)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if got == nil || got.ID != created.ID || got.Hash != hash {
+		t.Errorf("expected the key %+v but got %+v", created, got)
+	}
+
+	for _, u := range []struct {
+		date           string
+		requests, rows int
+	}{{"2024-05-01", 1, 0}, {"2024-05-01", 1, 20}, {"2024-05-02", 1, 5}} {
+		_, err := s.RecordUsage(ctx, created.ID, u.date, u.requests, u.rows)
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+	}
+
+	usage, err := s.Usage(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	want := []apikey.Usage{{Date: "2024-05-02", Requests: 1, Rows: 5}, {Date: "2024-05-01", Requests: 2, Rows: 20}}
+	if !reflect.DeepEqual(usage, want) {
+		t.Errorf("expected the usage %+v but got %+v", want, usage)
+	}
+
+	err = s.RevokeAPIKey(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	keys, err := s.APIKeys(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Errorf("expected the key to be revoked but got %+v", keys)
+	}
+}
@@ -1,152 +1,439 @@
 package tax
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
+	"github.com/Gitong23/assessment-tax/apikey"
 	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/metrics"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tracing"
 	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type (
 	Handler struct {
-		store Storer
+		store     Storer
+		deductors *DeductorCache
 	}
 
 	Storer interface {
-		PersonalAllowance() (*Allowances, error)
-		DonationAllowance() (*Allowances, error)
-		KreceiptAllowance() (*Allowances, error)
-		UpdateInitPersonalAllowance(amount float64) (*Allowances, error)
-		UpdateMaxAmountKreceipt(amount float64) (*Allowances, error)
-	}
-
-	Err struct {
-		Message string `json:"message"`
+		PersonalAllowance(ctx context.Context) (*Allowances, error)
+		DonationAllowance(ctx context.Context) (*Allowances, error)
+		KreceiptAllowance(ctx context.Context) (*Allowances, error)
+		UpdateInitPersonalAllowance(ctx context.Context, amount float64) (*Allowances, error)
+		UpdateMaxAmountKreceipt(ctx context.Context, amount float64) (*Allowances, error)
+		ExchangeRate(ctx context.Context, currency string, date string) (*ExchangeRate, error)
+		UpdateExchangeRates(ctx context.Context, rates []ExchangeRate) ([]ExchangeRate, error)
+		SampleTaxRequests(ctx context.Context) ([]TaxRequest, error)
+		UpdateSampleTaxRequests(ctx context.Context, samples []TaxRequest) error
 	}
 )
 
+// errJSON responds with a problem of status, code and message formatted
+// with args.
+func errJSON(c echo.Context, status int, code, message string, args ...interface{}) error {
+	return problem.JSON(c, problem.New(status, code, message).With(args...))
+}
+
+// problemJSON responds with the problem err carries, or with status when
+// err is a plain error.
+func problemJSON(c echo.Context, status int, err error) error {
+	return problem.JSON(c, problem.From(err, status))
+}
+
+// storeErr logs a failed store call and responds to it.
+func storeErr(c echo.Context, err error) error {
+	logger.FromContext(c.Request().Context()).Error("store call failed", "error", err)
+	status, message := helper.StoreError(err)
+	return errJSON(c, status, problem.Code(status), message)
+}
+
+// storeStatus logs a failed store call and returns the status and error to
+// respond with.
+func storeStatus(ctx context.Context, err error) (int, error) {
+	logger.FromContext(ctx).Error("store call failed", "error", err)
+	status, message := helper.StoreError(err)
+	return status, errors.New(message)
+}
+
+// fileErr logs an uploaded file that couldn't be opened and returns the
+// problem to respond with.
+func fileErr(ctx context.Context, err error) *problem.Error {
+	logger.FromContext(ctx).Error("opening uploaded file failed", "error", err)
+	return problem.New(http.StatusInternalServerError, problem.CodeInternal, "Internal Server Error")
+}
+
 func NewHandler(db Storer) *Handler {
-	return &Handler{store: db}
+	return &Handler{store: db, deductors: NewDeductorCache(db)}
+}
+
+// Deductors returns the cache the handler calculates with, so it can be
+// shared and invalidated when the allowances change elsewhere.
+func (h *Handler) Deductors() *DeductorCache {
+	return h.deductors
 }
 
 func (h *Handler) Tax(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.Tax").End()
 
 	reqTax := TaxRequest{}
 	if err := c.Bind(&reqTax); err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	res, status, err := h.calculate(c, &reqTax)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// calculate validates reqTax and calculates its tax. It returns the status
+// to respond with when it fails.
+func (h *Handler) calculate(c echo.Context, reqTax *TaxRequest) (TaxResponse, int, error) {
+	if err := c.Validate(*reqTax); err != nil {
+		return TaxResponse{}, http.StatusBadRequest, err
+	}
+
+	res, status, err := h.calculateValid(c.Request().Context(), reqTax)
+	if err != nil {
+		return TaxResponse{}, status, err
+	}
+	observeCalculation(res.summary.netIncome, res.Tax, res.TaxRefund != nil)
+	res.localize(i18n.For(c))
+
+	return res, http.StatusOK, nil
+}
+
+// calculateValid calculates the tax of a reqTax that follows the validator
+// rules, labelling the tax levels in the default language.
+func (h *Handler) calculateValid(ctx context.Context, reqTax *TaxRequest) (TaxResponse, int, error) {
+	err := reqTax.validateIncomes()
+	if err != nil {
+		return TaxResponse{}, http.StatusBadRequest, err
+	}
+
+	conversions, status, err := convertIncomes(ctx, h.store, reqTax)
+	if err != nil {
+		return TaxResponse{}, status, err
 	}
 
-	if err := c.Validate(reqTax); err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+	err = reqTax.validate()
+	if err != nil {
+		return TaxResponse{}, http.StatusBadRequest, err
+	}
+
+	deductor, err := h.deductors.Deductor(ctx)
+	if err != nil {
+		status, err := storeStatus(ctx, err)
+		return TaxResponse{}, status, err
+	}
+
+	_, span := tracing.Start(ctx, "tax.calculate")
+	res, err := deductor.calculate(*reqTax)
+	tracing.End(span, err)
+	if err != nil {
+		return TaxResponse{}, http.StatusBadRequest, err
+	}
+	res.Conversions = conversions
+
+	return res, http.StatusOK, nil
+}
+
+func (h *Handler) Household(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.Household").End()
+
+	reqHousehold := HouseholdRequest{}
+	if err := c.Bind(&reqHousehold); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	res, status, err := h.household(c, &reqHousehold)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// household validates reqHousehold and calculates the filing options of
+// the spouses. It returns the status to respond with when it fails.
+func (h *Handler) household(c echo.Context, reqHousehold *HouseholdRequest) (*HouseholdResponse, int, error) {
+	if err := c.Validate(*reqHousehold); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	spouses := []struct {
+		pointer string
+		t       *TaxRequest
+	}{
+		{"/taxpayer", &reqHousehold.Taxpayer},
+		{"/spouse", &reqHousehold.Spouse},
+	}
+	for _, s := range spouses {
+		err := s.t.validateIncomes()
+		if err != nil {
+			return nil, http.StatusBadRequest, problem.Nest(err, s.pointer)
+		}
+
+		_, status, err := convertIncomes(c.Request().Context(), h.store, s.t)
+		if err != nil {
+			return nil, status, problem.Nest(err, s.pointer)
+		}
+	}
+
+	err := reqHousehold.validate()
+	if err != nil {
+		return nil, http.StatusBadRequest, err
 	}
 
-	err := reqTax.validatWht()
+	deductor, err := h.deductors.Deductor(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		status, err := storeStatus(c.Request().Context(), err)
+		return nil, status, err
 	}
 
-	deductor, err := NewDeductor(h.store)
+	res, err := deductor.household(*reqHousehold)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: "Internal Server Error"})
+		return nil, http.StatusBadRequest, err
 	}
+	res.localize(i18n.For(c))
+
+	return res, http.StatusOK, nil
+}
 
-	err = deductor.checkMinAllowanceReq(reqTax.Allowances)
+func (h *Handler) Deductions(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.Deductions").End()
+	deductor, err := h.deductors.Deductor(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		return storeErr(c, err)
 	}
 
-	incomeTax := reqTax.TotalIncome - deductor.total(reqTax.Allowances)
-	return c.JSON(http.StatusOK, NewTaxResponse(reqTax.WHT, incomeTax))
+	return c.JSON(http.StatusOK, deductor.allowances())
 }
 
 func (h *Handler) UpdateInitPersonalDeduct(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.UpdateInitPersonalDeduct").End()
 	reqAmount := DeductionReq{}
 	if err := c.Bind(&reqAmount); err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return problem.JSON(c, problem.Bind(err))
 	}
 
 	if err := c.Validate(reqAmount); err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return problemJSON(c, http.StatusBadRequest, err)
 	}
 
-	status, err := validateInitPersonalDeduction(h.store, reqAmount.Amount)
+	status, err := validateInitPersonalDeduction(c.Request().Context(), h.store, reqAmount.Amount)
 	if err != nil {
-		return c.JSON(status, Err{Message: err.Error()})
+		return problemJSON(c, status, err)
+	}
+
+	if isDryRun(c) {
+		return dryRun(c, h.store, "personal", reqAmount.Amount)
 	}
-	p, err := h.store.UpdateInitPersonalAllowance(reqAmount.Amount)
+
+	p, err := h.store.UpdateInitPersonalAllowance(c.Request().Context(), reqAmount.Amount)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: "Internal Server Error"})
+		return storeErr(c, err)
 	}
+	h.deductors.Invalidate()
+	observeDeductionChange("personal", "updated")
 
 	return c.JSON(http.StatusOK, &InitPersonalDeductRes{PersonalDeduction: p.InitAmount})
 }
 
 func (h *Handler) UpdateMaxKreceiptDeduct(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.UpdateMaxKreceiptDeduct").End()
 
 	reqAmount := DeductionReq{}
 	if err := c.Bind(&reqAmount); err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return problem.JSON(c, problem.Bind(err))
 	}
 
 	if err := c.Validate(reqAmount); err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return problemJSON(c, http.StatusBadRequest, err)
 	}
 
-	status, err := validateMaxKreceipt(h.store, reqAmount.Amount)
+	status, err := validateMaxKreceipt(c.Request().Context(), h.store, reqAmount.Amount)
 	if err != nil {
-		return c.JSON(status, Err{Message: err.Error()})
+		return problemJSON(c, status, err)
+	}
+
+	if isDryRun(c) {
+		return dryRun(c, h.store, "k-receipt", reqAmount.Amount)
 	}
 
-	k, err := h.store.UpdateMaxAmountKreceipt(reqAmount.Amount)
+	k, err := h.store.UpdateMaxAmountKreceipt(c.Request().Context(), reqAmount.Amount)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: "Internal Server Error"})
+		return storeErr(c, err)
 	}
+	h.deductors.Invalidate()
+	observeDeductionChange("k-receipt", "updated")
 
 	return c.JSON(http.StatusOK, &MaxKreceiptRes{Kreceipt: k.MaxAmount})
 }
 
 func (h *Handler) UploadCsv(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.UploadCsv").End()
 
+	res, status, err := h.uploadCsv(c)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, res)
+}
+
+// uploadCsv calculates the tax of every row of the uploaded taxFile CSVs. It
+// returns the status to respond with when it fails.
+func (h *Handler) uploadCsv(c echo.Context) (*TaxUploadResponse, int, error) {
 	// Read form data
 	form, err := c.MultipartForm()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid form data"})
+		return nil, http.StatusBadRequest, problem.Invalid(CodeInvalidForm, "Invalid form data")
 	}
 
 	files := form.File["taxFile"]
 	if len(files) == 0 {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return nil, http.StatusBadRequest, problem.Invalid(problem.CodeInvalidBody, "Invalid request body")
 	}
 
 	// Check if files not have "taxFile" key
 	if !helper.IsFilesExt(".csv", files) {
-		return c.JSON(http.StatusBadRequest, Err{Message: "Only CSV files are allowed"})
+		return nil, http.StatusBadRequest, problem.Invalid(CodeUnsupportedFile, "Only CSV files are allowed")
 	}
 
 	src, err := OpenFormFile(files)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: "Internal Server Error"})
+		return nil, http.StatusInternalServerError, fileErr(c.Request().Context(), err)
 	}
 
+	_, span := tracing.Start(c.Request().Context(), "tax.parseCSV")
 	taxesReq, err := fileTaxReq(src)
+	tracing.End(span, err)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		rejectRows(rejectParse, 1)
+		return nil, http.StatusBadRequest, err
 	}
 
 	err = checkMultiWht(taxesReq)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		rejectRows(rejectWHT, len(taxesReq))
+		return nil, http.StatusBadRequest, err
+	}
+
+	err = apikey.ConsumeRows(c, len(taxesReq))
+	if errors.Is(err, apikey.ErrQuotaExceeded) {
+		rejectRows(rejectQuota, len(taxesReq))
+		return nil, http.StatusTooManyRequests, problem.New(http.StatusTooManyRequests, CodeRowQuotaExceeded, "CSV row quota exceeded")
+	}
+	if err != nil {
+		status, err := storeStatus(c.Request().Context(), err)
+		return nil, status, err
 	}
 
-	deductor, err := NewDeductor(h.store)
+	deductor, err := h.deductors.Deductor(c.Request().Context())
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, Err{Message: "Internal Server Error"})
+		status, err := storeStatus(c.Request().Context(), err)
+		return nil, status, err
 	}
 
 	err = deductor.checkMinMultiTaxReq(taxesReq)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, Err{Message: err.Error()})
+		rejectRows(rejectAllowance, len(taxesReq))
+		return nil, http.StatusBadRequest, err
+	}
+
+	_, span = tracing.Start(c.Request().Context(), "tax.calculate", attribute.Int("rows", len(taxesReq)))
+	res := NewTaxUploadResponse(taxesReq, deductor)
+	span.End()
+
+	for i, t := range res.Taxs {
+		observeCalculation(deductor.netIncome(taxesReq[i]), t.Tax, t.TaxRefund != nil)
+	}
+
+	metrics.CSVRowsProcessed.Add(float64(len(taxesReq)))
+	return res, http.StatusOK, nil
+}
+
+func (h *Handler) UploadSampleCsv(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.UploadSampleCsv").End()
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, CodeInvalidForm, "Invalid form data")
+	}
+
+	files := form.File["taxFile"]
+	if len(files) == 0 {
+		return errJSON(c, http.StatusBadRequest, problem.CodeInvalidBody, "Invalid request body")
+	}
+
+	if !helper.IsFilesExt(".csv", files) {
+		return errJSON(c, http.StatusBadRequest, CodeUnsupportedFile, "Only CSV files are allowed")
+	}
+
+	src, err := OpenFormFile(files)
+	if err != nil {
+		return problem.JSON(c, fileErr(c.Request().Context(), err))
+	}
+
+	taxesReq, err := fileSampleReq(src)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	err = checkMultiWht(taxesReq)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	err = h.store.UpdateSampleTaxRequests(c.Request().Context(), taxesReq)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &SampleUploadResponse{Population: len(taxesReq)})
+}
+
+func (h *Handler) UploadExchangeRateCsv(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.UploadExchangeRateCsv").End()
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return errJSON(c, http.StatusBadRequest, CodeInvalidForm, "Invalid form data")
+	}
+
+	files := form.File["rateFile"]
+	if len(files) == 0 {
+		return errJSON(c, http.StatusBadRequest, problem.CodeInvalidBody, "Invalid request body")
+	}
+
+	if !helper.IsFilesExt(".csv", files) {
+		return errJSON(c, http.StatusBadRequest, CodeUnsupportedFile, "Only CSV files are allowed")
+	}
+
+	src, err := OpenFormFile(files)
+	if err != nil {
+		return problem.JSON(c, fileErr(c.Request().Context(), err))
+	}
+
+	rates, err := fileExchangeRates(src)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	updated, err := h.store.UpdateExchangeRates(c.Request().Context(), rates)
+	if err != nil {
+		return storeErr(c, err)
 	}
 
-	return c.JSON(http.StatusOK, NewTaxUploadResponse(taxesReq, deductor))
+	return c.JSON(http.StatusOK, &ExchangeRateUploadResponse{Rates: updated})
 }
@@ -1,7 +1,9 @@
 package tax
 
+import "log/slog"
+
 type AllowanceReq struct {
-	AllowanceType string  `json:"allowanceType"`
+	AllowanceType string  `json:"allowanceType" validate:"required,oneof=donation k-receipt"`
 	Amount        float64 `json:"amount"`
 }
 
@@ -15,10 +17,41 @@ type Allowances struct {
 	CreatedAt      string  `json:"created_at"`
 }
 
+type CreditReq struct {
+	CreditType string  `json:"creditType"`
+	Amount     float64 `json:"amount"`
+	Rate       float64 `json:"rate,omitempty"`
+	TaxPaid    float64 `json:"taxPaid,omitempty"`
+}
+
+type IncomeReq struct {
+	Amount       float64 `json:"amount"`
+	WHT          float64 `json:"wht"`
+	Currency     string  `json:"currency"`
+	ReceivedDate string  `json:"receivedDate"`
+}
+
 type TaxRequest struct {
-	TotalIncome float64        `json:"totalIncome"`
+	TotalIncome float64        `json:"totalIncome" validate:"gte=0"`
 	WHT         float64        `json:"wht"`
-	Allowances  []AllowanceReq `json:"allowances"`
+	Incomes     []IncomeReq    `json:"incomes,omitempty"`
+	Period      string         `json:"period,omitempty"`
+	HalfYearTax float64        `json:"halfYearTax,omitempty"`
+	FilingDate  string         `json:"filingDate,omitempty"`
+	DueDate     string         `json:"dueDate,omitempty"`
+	Penalty     string         `json:"penalty,omitempty"`
+	Allowances  []AllowanceReq `json:"allowances" validate:"dive"`
+	Credits     []CreditReq    `json:"credits,omitempty"`
+}
+
+// LogValue keeps the incomes and amounts of a tax request out of the logs.
+func (t TaxRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("period", t.period()),
+		slog.Int("incomes", len(t.Incomes)),
+		slog.Int("allowances", len(t.Allowances)),
+		slog.Int("credits", len(t.Credits)),
+	)
 }
 
 type TaxLevel struct {
@@ -26,14 +59,69 @@ type TaxLevel struct {
 	Tax   float64 `json:"tax"`
 }
 
+type CreditRes struct {
+	CreditType string  `json:"creditType"`
+	Amount     float64 `json:"amount"`
+	Applied    float64 `json:"applied"`
+	Unused     float64 `json:"unused"`
+}
+
+type Conversion struct {
+	Currency     string  `json:"currency"`
+	ReceivedDate string  `json:"receivedDate"`
+	RateDate     string  `json:"rateDate"`
+	Rate         float64 `json:"rate"`
+	Amount       float64 `json:"amount"`
+	WHT          float64 `json:"wht"`
+	AmountTHB    float64 `json:"amountThb"`
+	WHTTHB       float64 `json:"whtThb"`
+}
+
 type TaxResponse struct {
-	Tax       float64     `json:"tax"`
-	TaxRefund interface{} `json:"taxRefund,omitempty"`
-	TaxLevels []TaxLevel  `json:"taxLevels,omitempty"`
+	Tax         float64      `json:"tax"`
+	TaxRefund   interface{}  `json:"taxRefund,omitempty"`
+	TaxLevels   []TaxLevel   `json:"taxLevels,omitempty"`
+	Credits     []CreditRes  `json:"credits,omitempty"`
+	Surcharge   *Surcharge   `json:"surcharge,omitempty"`
+	Conversions []Conversion `json:"conversions,omitempty"`
+
+	// summary is left out of the v1 contract and answered in v2.
+	summary summary
+}
+
+type HouseholdRequest struct {
+	Taxpayer TaxRequest `json:"taxpayer"`
+	Spouse   TaxRequest `json:"spouse"`
+}
+
+type HouseholdOption struct {
+	Filing    string        `json:"filing"`
+	Tax       float64       `json:"tax"`
+	TaxRefund float64       `json:"taxRefund"`
+	Results   []TaxResponse `json:"results"`
+}
+
+type HouseholdResponse struct {
+	Options     []HouseholdOption `json:"options"`
+	Recommended string            `json:"recommended"`
 }
 
 type DeductionReq struct {
-	Amount float64 `json:"amount"`
+	Amount float64 `json:"amount" form:"amount"`
+}
+
+type Impact struct {
+	Type           string  `json:"type"`
+	Current        float64 `json:"current"`
+	Proposed       float64 `json:"proposed"`
+	Population     int     `json:"population"`
+	CurrentTax     float64 `json:"currentTax"`
+	ProposedTax    float64 `json:"proposedTax"`
+	TotalChange    float64 `json:"totalChange"`
+	AverageChange  float64 `json:"averageChange"`
+	Increased      int     `json:"increased"`
+	Decreased      int     `json:"decreased"`
+	BracketChanges int     `json:"bracketChanges"`
 }
 
 type InitPersonalDeductRes struct {
@@ -53,3 +141,40 @@ type TaxUpload struct {
 type TaxUploadResponse struct {
 	Taxs []TaxUpload `json:"taxs"`
 }
+
+type SampleUploadResponse struct {
+	Population int `json:"population"`
+}
+
+type ExchangeRate struct {
+	Date     string  `json:"date"`
+	Currency string  `json:"currency"`
+	Rate     float64 `json:"rate"`
+}
+
+type ExchangeRateUploadResponse struct {
+	Rates []ExchangeRate `json:"rates"`
+}
+
+type ChangeComment struct {
+	ID        int    `json:"id"`
+	Author    string `json:"author"`
+	Comment   string `json:"comment"`
+	CreatedAt string `json:"created_at"`
+}
+
+type DeductionChange struct {
+	ID          int             `json:"id"`
+	Type        string          `json:"type"`
+	Amount      float64         `json:"amount"`
+	Status      string          `json:"status"`
+	SubmittedBy string          `json:"submittedBy"`
+	DecidedBy   string          `json:"decidedBy,omitempty"`
+	CreatedAt   string          `json:"created_at"`
+	DecidedAt   string          `json:"decided_at,omitempty"`
+	Comments    []ChangeComment `json:"comments,omitempty"`
+}
+
+type CommentReq struct {
+	Comment string `json:"comment"`
+}
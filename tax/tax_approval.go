@@ -0,0 +1,225 @@
+package tax
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Gitong23/assessment-tax/auth"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	ChangePending  = "pending"
+	ChangeApproved = "approved"
+	ChangeRejected = "rejected"
+)
+
+type (
+	// ApprovalHandler lets admins submit deduction changes that only apply
+	// once a second admin approves them.
+	ApprovalHandler struct {
+		store     Storer
+		changes   ChangeStorer
+		deductors *DeductorCache
+	}
+
+	ChangeStorer interface {
+		CreateDeductionChange(ctx context.Context, change DeductionChange) (*DeductionChange, error)
+		DeductionChanges(ctx context.Context, status string) ([]DeductionChange, error)
+		DeductionChange(ctx context.Context, id int) (*DeductionChange, error)
+		// DecideDeductionChange sets the status of a pending change and, when
+		// it is approved, applies it to the allowances in the same
+		// transaction.
+		DecideDeductionChange(ctx context.Context, id int, status string, by string) (*DeductionChange, error)
+		AddDeductionChangeComment(ctx context.Context, id int, author string, comment string) (*ChangeComment, error)
+	}
+)
+
+// NewApprovalHandler invalidates d whenever an approved change is applied.
+func NewApprovalHandler(s Storer, c ChangeStorer, d *DeductorCache) *ApprovalHandler {
+	return &ApprovalHandler{store: s, changes: c, deductors: d}
+}
+
+// validateChange checks the change amount against the current limits of
+// its allowance.
+func validateChange(ctx context.Context, s Storer, change *DeductionChange) (int, error) {
+	switch change.Type {
+	case "personal":
+		return validateInitPersonalDeduction(ctx, s, change.Amount)
+	case "k-receipt":
+		return validateMaxKreceipt(ctx, s, change.Amount)
+	}
+	return http.StatusBadRequest, problem.Invalid(CodeInvalidDeductionType, "Invalid deduction type")
+}
+
+func username(c echo.Context) string {
+	if u := auth.CurrentUser(c); u != nil {
+		return u.Username
+	}
+	return ""
+}
+
+func (h *ApprovalHandler) submit(c echo.Context, t string) error {
+	reqAmount := DeductionReq{}
+	if err := c.Bind(&reqAmount); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	if err := c.Validate(reqAmount); err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	change := DeductionChange{
+		Type:        t,
+		Amount:      reqAmount.Amount,
+		Status:      ChangePending,
+		SubmittedBy: username(c),
+	}
+
+	status, err := validateChange(c.Request().Context(), h.store, &change)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	if isDryRun(c) {
+		return dryRun(c, h.store, t, change.Amount)
+	}
+
+	created, err := h.changes.CreateDeductionChange(c.Request().Context(), change)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	observeDeductionChange(t, "submitted")
+	return c.JSON(http.StatusAccepted, created)
+}
+
+func (h *ApprovalHandler) SubmitPersonalDeduct(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.SubmitPersonalDeduct").End()
+	return h.submit(c, "personal")
+}
+
+func (h *ApprovalHandler) SubmitKreceiptDeduct(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.SubmitKreceiptDeduct").End()
+	return h.submit(c, "k-receipt")
+}
+
+func (h *ApprovalHandler) Changes(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.Changes").End()
+	status := c.QueryParam("status")
+	if status != "" && status != ChangePending && status != ChangeApproved && status != ChangeRejected {
+		return errJSON(c, http.StatusBadRequest, CodeInvalidStatus, "Invalid status")
+	}
+
+	changes, err := h.changes.DeductionChanges(c.Request().Context(), status)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, changes)
+}
+
+// change returns the change in the request path, or writes the error
+// response when there is none.
+func (h *ApprovalHandler) change(c echo.Context) (*DeductionChange, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return nil, errJSON(c, http.StatusBadRequest, CodeInvalidChangeID, "Invalid change id")
+	}
+
+	change, err := h.changes.DeductionChange(c.Request().Context(), id)
+	if err != nil {
+		return nil, storeErr(c, err)
+	}
+
+	if change == nil {
+		return nil, errJSON(c, http.StatusNotFound, CodeChangeNotFound, "Deduction change not found")
+	}
+	return change, nil
+}
+
+func (h *ApprovalHandler) Change(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.Change").End()
+	change, err := h.change(c)
+	if change == nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, change)
+}
+
+func (h *ApprovalHandler) decide(c echo.Context, status string) error {
+	change, err := h.change(c)
+	if change == nil {
+		return err
+	}
+
+	if change.Status != ChangePending {
+		return errJSON(c, http.StatusConflict, CodeChangeDecided, "Deduction change is already %s", change.Status)
+	}
+
+	by := username(c)
+	if by == "" || by == change.SubmittedBy {
+		return errJSON(c, http.StatusForbidden, CodeSelfApproval, "Deduction change must be decided by another admin")
+	}
+
+	if status == ChangeApproved {
+		code, err := validateChange(c.Request().Context(), h.store, change)
+		if err != nil {
+			return problemJSON(c, code, err)
+		}
+	}
+
+	decided, err := h.changes.DecideDeductionChange(c.Request().Context(), change.ID, status, by)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	if decided == nil {
+		return errJSON(c, http.StatusConflict, CodeChangeNotPending, "Deduction change is no longer pending")
+	}
+
+	if decided.Status == ChangeApproved {
+		h.deductors.Invalidate()
+	}
+	observeDeductionChange(decided.Type, decided.Status)
+
+	return c.JSON(http.StatusOK, decided)
+}
+
+func (h *ApprovalHandler) Approve(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.Approve").End()
+	return h.decide(c, ChangeApproved)
+}
+
+func (h *ApprovalHandler) Reject(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.Reject").End()
+	return h.decide(c, ChangeRejected)
+}
+
+func (h *ApprovalHandler) Comment(c echo.Context) error {
+	defer tracing.Span(c, "tax.ApprovalHandler.Comment").End()
+	change, err := h.change(c)
+	if change == nil {
+		return err
+	}
+
+	reqComment := CommentReq{}
+	if err := c.Bind(&reqComment); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	if reqComment.Comment == "" {
+		return problemJSON(c, http.StatusBadRequest, invalid(CodeInvalidComment, "/comment", "required", "", "Invalid comment"))
+	}
+
+	comment, err := h.changes.AddDeductionChangeComment(c.Request().Context(), change.ID, username(c), reqComment.Comment)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, comment)
+}
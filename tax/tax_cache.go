@@ -0,0 +1,52 @@
+package tax
+
+import (
+	"context"
+	"sync"
+)
+
+// DeductorCache keeps the Deductor built from the stored allowances so tax
+// calculations don't read them on every request. It's safe for concurrent
+// use and reloads the allowances on the first use after Invalidate.
+type DeductorCache struct {
+	store Storer
+
+	mu sync.RWMutex
+	d  *Deductor
+}
+
+func NewDeductorCache(s Storer) *DeductorCache {
+	return &DeductorCache{store: s}
+}
+
+// Deductor returns the cached Deductor, loading it from the store when the
+// cache is empty.
+func (dc *DeductorCache) Deductor(ctx context.Context) (*Deductor, error) {
+	dc.mu.RLock()
+	d := dc.d
+	dc.mu.RUnlock()
+	if d != nil {
+		return d, nil
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.d != nil {
+		return dc.d, nil
+	}
+
+	d, err := NewDeductor(ctx, dc.store)
+	if err != nil {
+		return nil, err
+	}
+	dc.d = d
+	return d, nil
+}
+
+// Invalidate drops the cached Deductor so the next use reads the
+// allowances again.
+func (dc *DeductorCache) Invalidate() {
+	dc.mu.Lock()
+	dc.d = nil
+	dc.mu.Unlock()
+}
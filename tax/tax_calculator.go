@@ -0,0 +1,155 @@
+package tax
+
+import (
+	"context"
+	"io"
+
+	"github.com/Gitong23/assessment-tax/i18n"
+)
+
+type (
+	// AllowanceUse is how much of an allowance was claimed and deducted.
+	AllowanceUse struct {
+		Type     string  `json:"type"`
+		Claimed  float64 `json:"claimed"`
+		Deducted float64 `json:"deducted"`
+	}
+
+	// LevelUse is the part of the net income taxed at a level.
+	LevelUse struct {
+		Level  string  `json:"level"`
+		Rate   float64 `json:"rate"`
+		Income float64 `json:"income"`
+		Tax    float64 `json:"tax"`
+	}
+
+	// Explanation is a tax response with the steps that reached it.
+	Explanation struct {
+		Response   TaxResponseV2  `json:"response"`
+		Allowances []AllowanceUse `json:"allowances"`
+		Levels     []LevelUse     `json:"levels"`
+	}
+)
+
+// Calculator calculates taxes the way the handlers do, for callers without
+// a server such as the ktax command.
+type Calculator struct {
+	handler   *Handler
+	validator *Validator
+}
+
+// NewCalculator calculates with the allowances and exchange rates of s.
+func NewCalculator(s Storer) *Calculator {
+	return &Calculator{handler: NewHandler(s), validator: NewValidator()}
+}
+
+// calculate validates req and calculates its tax the way TaxV2 does,
+// labelling the tax levels in the language of p. It converts the incomes
+// of req in place.
+func (c *Calculator) calculate(ctx context.Context, req *TaxRequestV2, p *i18n.Printer) (int, TaxResponse, error) {
+	year, err := taxYear(req.TaxYear)
+	if err != nil {
+		return 0, TaxResponse{}, err
+	}
+
+	if err := c.validator.Validate(req.TaxRequest); err != nil {
+		return 0, TaxResponse{}, err
+	}
+
+	res, _, err := c.handler.calculateValid(ctx, &req.TaxRequest)
+	if err != nil {
+		return 0, TaxResponse{}, err
+	}
+	res.localize(p)
+
+	return year, res, nil
+}
+
+// Tax answers req the way TaxV2 does.
+func (c *Calculator) Tax(ctx context.Context, req TaxRequestV2, p *i18n.Printer) (TaxResponseV2, error) {
+	year, res, err := c.calculate(ctx, &req, p)
+	if err != nil {
+		return TaxResponseV2{}, err
+	}
+	return newTaxResponseV2(year, res), nil
+}
+
+// Explain answers req with the allowances deducted from its income and the
+// part of the net income taxed at every level.
+func (c *Calculator) Explain(ctx context.Context, req TaxRequestV2, p *i18n.Printer) (*Explanation, error) {
+	year, res, err := c.calculate(ctx, &req, p)
+	if err != nil {
+		return nil, err
+	}
+
+	deductor, err := c.handler.deductors.Deductor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	period := req.period()
+	personal := deductor.personal(period)
+	e := &Explanation{
+		Response:   newTaxResponseV2(year, res),
+		Allowances: []AllowanceUse{{Type: "personal", Claimed: personal, Deducted: personal}},
+	}
+	for _, a := range req.Allowances {
+		e.Allowances = append(e.Allowances, AllowanceUse{
+			Type:     a.AllowanceType,
+			Claimed:  a.Amount,
+			Deducted: deductor.add(a.AllowanceType, a.Amount, period),
+		})
+	}
+
+	netIncome := deductor.netIncome(req.TaxRequest)
+	for idx, s := range steps {
+		income := min(max(netIncome-s.Min, 0), s.Max-s.Min)
+		e.Levels = append(e.Levels, LevelUse{
+			Level:  levelLabel(idx, p),
+			Rate:   s.Rate,
+			Income: income,
+			Tax:    s.taxStep(income),
+		})
+	}
+	return e, nil
+}
+
+// ReadTaxCSV reads the rows of a CSV file in the upload-csv format.
+func ReadTaxCSV(r io.Reader) ([]TaxRequest, error) {
+	records, err := readFileCsv(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var taxesReq []TaxRequest
+	err = appendTaxReq(&taxesReq, records)
+	if err != nil {
+		return nil, err
+	}
+	return taxesReq, nil
+}
+
+// Batch answers the rows of a CSV file the way UploadCsvV2 does.
+func (c *Calculator) Batch(ctx context.Context, year int, taxesReq []TaxRequest) (TaxUploadResponseV2, error) {
+	year, err := taxYear(year)
+	if err != nil {
+		return TaxUploadResponseV2{}, err
+	}
+
+	err = checkMultiWht(taxesReq)
+	if err != nil {
+		return TaxUploadResponseV2{}, err
+	}
+
+	deductor, err := c.handler.deductors.Deductor(ctx)
+	if err != nil {
+		return TaxUploadResponseV2{}, err
+	}
+
+	err = deductor.checkMinMultiTaxReq(taxesReq)
+	if err != nil {
+		return TaxUploadResponseV2{}, err
+	}
+
+	return newTaxUploadResponseV2(year, NewTaxUploadResponse(taxesReq, deductor)), nil
+}
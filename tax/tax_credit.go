@@ -0,0 +1,107 @@
+package tax
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Gitong23/assessment-tax/problem"
+)
+
+const (
+	CreditDividend = "dividend"
+	CreditForeign  = "foreign"
+)
+
+// grossDividend is the dividend grossed up with the corporate tax it was paid
+// from, which the filer adds to income to claim the dividend credit.
+func (c *CreditReq) grossDividend() float64 {
+	return c.Amount / (1 - c.Rate)
+}
+
+func (c *CreditReq) validate() error {
+	switch c.CreditType {
+	case CreditDividend:
+		if c.Rate <= 0 {
+			return invalid(CodeInvalidCredit, "/rate", "gt", "0", "Invalid dividend rate")
+		}
+		if c.Rate >= 1 {
+			return invalid(CodeInvalidCredit, "/rate", "lt", "1", "Invalid dividend rate")
+		}
+	case CreditForeign:
+		if c.TaxPaid < 0 {
+			return invalid(CodeInvalidCredit, "/taxPaid", "gte", "0", "Invalid foreign tax paid")
+		}
+	default:
+		return invalid(CodeInvalidCredit, "/creditType", "oneof", CreditDividend+" "+CreditForeign, "Invalid credit type")
+	}
+
+	if c.Amount < 0 {
+		return invalid(CodeInvalidCredit, "/amount", "gte", "0", "Invalid %s amount", c.CreditType)
+	}
+	return nil
+}
+
+func (t *TaxRequest) validateCredits() error {
+	foreign := 0.0
+	for i, c := range t.Credits {
+		err := c.validate()
+		if err != nil {
+			return problem.Nest(err, fmt.Sprintf("/credits/%d", i))
+		}
+
+		if c.CreditType == CreditForeign {
+			foreign += c.Amount
+		}
+	}
+
+	if foreign > t.TotalIncome {
+		return invalid(CodeForeignIncome, "/credits", "lte", "/totalIncome", "Foreign income can't be more than total income")
+	}
+	return nil
+}
+
+func (t *TaxRequest) grossDividend() float64 {
+	result := 0.0
+	for _, c := range t.Credits {
+		if c.CreditType == CreditDividend {
+			result += c.grossDividend()
+		}
+	}
+	return result
+}
+
+// newCredits applies the requested credits against the tax on netIncome and
+// returns them with the total amount credited. The foreign tax credit is
+// limited to the Thai tax on the foreign income, while the dividend credit
+// is refundable.
+func newCredits(t *TaxRequest, netIncome float64) ([]CreditRes, float64) {
+	tax := calLevelTax(netIncome)
+	income := t.TotalIncome + t.grossDividend()
+
+	var credits []CreditRes
+	total := 0.0
+	for _, c := range t.Credits {
+		var claimed, applied float64
+		switch c.CreditType {
+		case CreditDividend:
+			claimed = c.grossDividend() - c.Amount
+			applied = claimed
+		case CreditForeign:
+			claimed = c.TaxPaid
+			// Without income there's no Thai tax on the foreign part of it.
+			if income > 0 {
+				applied = math.Min(claimed, tax*c.Amount/income)
+			}
+		}
+
+		credits = append(credits, CreditRes{
+			CreditType: c.CreditType,
+			Amount:     claimed,
+			Applied:    applied,
+			Unused:     claimed - applied,
+		})
+		total += applied
+	}
+
+	return credits, total
+}
@@ -1,26 +1,43 @@
 package tax
 
 import (
+	"context"
 	"fmt"
+
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tracing"
 )
 
+var allowanceTypes = []string{"personal", "donation", "k-receipt"}
+
+// DefaultAllowances returns the allowances the allowances migration seeds.
+func DefaultAllowances() []Allowances {
+	return []Allowances{
+		{ID: 1, Type: "personal", InitAmount: 60000, MinAmount: 10000, MaxAmount: 100000, LimitMaxAmount: 100000},
+		{ID: 2, Type: "donation", InitAmount: 0, MinAmount: 0, MaxAmount: 100000, LimitMaxAmount: 100000},
+		{ID: 3, Type: "k-receipt", InitAmount: 0, MinAmount: 0, MaxAmount: 50000, LimitMaxAmount: 100000},
+	}
+}
+
 type Deductor struct {
 	m map[string]*Allowances
 }
 
-func NewDeductor(db Storer) (*Deductor, error) {
+func NewDeductor(ctx context.Context, db Storer) (_ *Deductor, err error) {
+	ctx, span := tracing.Start(ctx, "tax.NewDeductor")
+	defer func() { tracing.End(span, err) }()
 
-	personal, err := db.PersonalAllowance()
+	personal, err := db.PersonalAllowance(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	donation, err := db.DonationAllowance()
+	donation, err := db.DonationAllowance(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	kReceipt, err := db.KreceiptAllowance()
+	kReceipt, err := db.KreceiptAllowance(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +51,14 @@ func NewDeductor(db Storer) (*Deductor, error) {
 	}, nil
 }
 
+func (d *Deductor) allowances() []Allowances {
+	var a []Allowances
+	for _, t := range allowanceTypes {
+		a = append(a, *d.m[t])
+	}
+	return a
+}
+
 func (d *Deductor) min(t string) float64 {
 	return d.m[t].MinAmount
 }
@@ -48,42 +73,70 @@ func (d *Deductor) initPer(t string) float64 {
 
 func (d *Deductor) validateMin(a float64, t string) error {
 	if a < d.min(t) {
-		return fmt.Errorf("Invalid %s amount", t)
+		return invalid(CodeInvalidAllowanceAmount, "/amount", "gte", limit(d.min(t)), "Invalid %s amount", t)
 	}
 	return nil
 }
 
 func (d *Deductor) checkMinAllowanceReq(a []AllowanceReq) error {
-	for _, e := range a {
+	for i, e := range a {
 		err := d.validateMin(e.Amount, e.AllowanceType)
 		if err != nil {
-			return err
+			return problem.Nest(err, fmt.Sprintf("/allowances/%d", i))
 		}
 	}
 	return nil
 }
 
-func (d *Deductor) add(t string, a float64) float64 {
-	if a > d.max(t) {
-		return d.max(t)
+func (d *Deductor) add(t string, a float64, p string) float64 {
+	max := d.max(t)
+	if p == PeriodHalfYear {
+		if annualOnly[t] {
+			return 0
+		}
+		max *= halfYearRate
+	}
+
+	if a > max {
+		return max
 	}
 	return a
 }
 
-func (d *Deductor) total(a []AllowanceReq) float64 {
+func (d *Deductor) personal(p string) float64 {
+	if p == PeriodHalfYear {
+		return d.initPer("personal") * halfYearRate
+	}
+	return d.initPer("personal")
+}
+
+func (d *Deductor) total(a []AllowanceReq, p string) float64 {
 	result := 0.0
 	for _, e := range a {
-		result += d.add(e.AllowanceType, e.Amount)
+		result += d.add(e.AllowanceType, e.Amount, p)
+	}
+
+	return result + d.personal(p)
+}
+
+func (d *Deductor) netIncome(t TaxRequest) float64 {
+	return t.TotalIncome + t.grossDividend() - d.total(t.Allowances, t.period())
+}
+
+func (d *Deductor) calculate(t TaxRequest) (TaxResponse, error) {
+	err := d.checkMinAllowanceReq(t.Allowances)
+	if err != nil {
+		return TaxResponse{}, err
 	}
 
-	return result + d.initPer("personal")
+	return t.response(d.netIncome(t))
 }
 
 func (d *Deductor) checkMinMultiTaxReq(taxesReq []TaxRequest) error {
-	for _, taxReq := range taxesReq {
+	for i, taxReq := range taxesReq {
 		err := d.checkMinAllowanceReq(taxReq.Allowances)
 		if err != nil {
-			return err
+			return problem.Nest(err, fmt.Sprintf("/rows/%d", i))
 		}
 	}
 	return nil
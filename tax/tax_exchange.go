@@ -0,0 +1,168 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Gitong23/assessment-tax/problem"
+)
+
+const baht = "THB"
+
+// RateWindow is how many days back the rate of an income may be looked up
+// from its received date, which covers weekends and holidays without
+// converting a currency missing from the table at a stale rate.
+const RateWindow = 7
+
+var currencyCode = regexp.MustCompile("^[A-Z]{3}$")
+
+func (i *IncomeReq) currency() string {
+	return strings.ToUpper(i.Currency)
+}
+
+// StaleRateDate returns the latest date whose rate is too old for an income
+// received on date.
+func StaleRateDate(date string) (string, error) {
+	d, err := parseDate(date)
+	if err != nil {
+		return "", err
+	}
+	return d.AddDate(0, 0, -RateWindow).Format(dateLayout), nil
+}
+
+func (i *IncomeReq) validate() error {
+	if !currencyCode.MatchString(i.currency()) {
+		return invalid(CodeInvalidCurrency, "/currency", "iso4217", "", "Invalid currency code")
+	}
+
+	if _, err := parseDate(i.ReceivedDate); err != nil {
+		return invalid(CodeInvalidDate, "/receivedDate", "date", dateLayout, "Invalid received date")
+	}
+
+	if i.Amount < 0 {
+		return invalid(CodeInvalidIncomeAmount, "/amount", "gte", "0", "Invalid income amount")
+	}
+
+	if i.WHT < 0 {
+		return invalid(CodeInvalidWHT, "/wht", "gte", "0", "Invalid WHT value")
+	}
+
+	if i.WHT > i.Amount {
+		return invalid(CodeInvalidWHT, "/wht", "lte", "/amount", "Invalid WHT value")
+	}
+	return nil
+}
+
+func (t *TaxRequest) validateIncomes() error {
+	for idx, i := range t.Incomes {
+		err := i.validate()
+		if err != nil {
+			return problem.Nest(err, fmt.Sprintf("/incomes/%d", idx))
+		}
+	}
+	return nil
+}
+
+// convertIncomes converts the request's incomes to baht with the exchange
+// rate of the day they were received and adds them to the total income and
+// WHT.
+func convertIncomes(ctx context.Context, s Storer, t *TaxRequest) ([]Conversion, int, error) {
+	var conversions []Conversion
+	for idx, i := range t.Incomes {
+		rate := &ExchangeRate{Date: i.ReceivedDate, Currency: baht, Rate: 1}
+		if i.currency() != baht {
+			var err error
+			rate, err = s.ExchangeRate(ctx, i.currency(), i.ReceivedDate)
+			if err != nil {
+				status, err := storeStatus(ctx, err)
+				return nil, status, err
+			}
+
+			if rate == nil {
+				pointer := fmt.Sprintf("/incomes/%d/receivedDate", idx)
+				message := "No exchange rate for %s on %s"
+				return nil, http.StatusBadRequest, invalid(CodeNoExchangeRate, pointer, "exchange_rate", i.currency(), message, i.currency(), i.ReceivedDate)
+			}
+		}
+
+		c := Conversion{
+			Currency:     rate.Currency,
+			ReceivedDate: i.ReceivedDate,
+			RateDate:     rate.Date,
+			Rate:         rate.Rate,
+			Amount:       i.Amount,
+			WHT:          i.WHT,
+			AmountTHB:    i.Amount * rate.Rate,
+			WHTTHB:       i.WHT * rate.Rate,
+		}
+		t.TotalIncome += c.AmountTHB
+		t.WHT += c.WHTTHB
+		conversions = append(conversions, c)
+	}
+
+	return conversions, http.StatusOK, nil
+}
+
+func isCorrectRateHeader(record []string) bool {
+	if len(record) != 3 || record[0] != "date" || record[1] != "currency" || record[2] != "rate" {
+		return false
+	}
+	return true
+}
+
+func csvExchangeRate(record []string) (*ExchangeRate, error) {
+	if len(record) != 3 {
+		return nil, problem.Invalid(CodeInvalidCSV, "Invalid CSV file content")
+	}
+
+	if _, err := parseDate(record[0]); err != nil {
+		return nil, invalid(CodeInvalidDate, "/date", "date", dateLayout, "Invalid date value")
+	}
+
+	currency := strings.ToUpper(record[1])
+	if !currencyCode.MatchString(currency) || currency == baht {
+		return nil, invalid(CodeInvalidCurrency, "/currency", "iso4217", "", "Invalid currency value")
+	}
+
+	rate, err := strconv.ParseFloat(record[2], 64)
+	if err != nil || rate <= 0 {
+		return nil, invalid(CodeInvalidRate, "/rate", "gt", "0", "Invalid rate value")
+	}
+
+	return &ExchangeRate{
+		Date:     record[0],
+		Currency: currency,
+		Rate:     rate,
+	}, nil
+}
+
+func fileExchangeRates(src []multipart.File) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+	for _, s := range src {
+		records, err := readFileCsv(s)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx, r := range records {
+			if idx == 0 {
+				if !isCorrectRateHeader(r) {
+					return nil, problem.Invalid(CodeInvalidCSVHeader, "Invalid CSV header")
+				}
+				continue
+			}
+
+			rate, err := csvExchangeRate(r)
+			if err != nil {
+				return nil, problem.Nest(err, fmt.Sprintf("/rows/%d", len(rates)))
+			}
+			rates = append(rates, *rate)
+		}
+	}
+	return rates, nil
+}
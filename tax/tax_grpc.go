@@ -0,0 +1,261 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/apikey"
+	"github.com/Gitong23/assessment-tax/auth"
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/taxpb"
+	"github.com/Gitong23/assessment-tax/tracing"
+)
+
+// GRPCServer serves the tax service over gRPC with the engine and stores of
+// the REST handlers.
+type GRPCServer struct {
+	taxpb.UnimplementedTaxServiceServer
+
+	handler   *Handler
+	approvals *ApprovalHandler
+	validator *Validator
+}
+
+// NewGRPCServer submits deduction changes to approvals for approval, or
+// applies them directly when approvals is nil.
+func NewGRPCServer(h *Handler, approvals *ApprovalHandler) *GRPCServer {
+	return &GRPCServer{handler: h, approvals: approvals, validator: NewValidator()}
+}
+
+// grpcErr returns the problem err carries, or status when err is a plain
+// error, as a gRPC error in the language of the call.
+func grpcErr(ctx context.Context, status int, err error) error {
+	return problem.GRPC(problem.From(err, status), i18n.ForGRPC(ctx))
+}
+
+func newTaxRequest(req *taxpb.TaxRequest) TaxRequest {
+	t := TaxRequest{
+		TotalIncome: req.TotalIncome,
+		WHT:         req.Wht,
+		Period:      req.Period,
+		HalfYearTax: req.HalfYearTax,
+		FilingDate:  req.FilingDate,
+		DueDate:     req.DueDate,
+		Penalty:     req.Penalty,
+	}
+	for _, a := range req.Allowances {
+		t.Allowances = append(t.Allowances, AllowanceReq{AllowanceType: a.AllowanceType, Amount: a.Amount})
+	}
+	for _, i := range req.Incomes {
+		t.Incomes = append(t.Incomes, IncomeReq{Amount: i.Amount, WHT: i.Wht, Currency: i.Currency, ReceivedDate: i.ReceivedDate})
+	}
+	for _, c := range req.Credits {
+		t.Credits = append(t.Credits, CreditReq{CreditType: c.CreditType, Amount: c.Amount, Rate: c.Rate, TaxPaid: c.TaxPaid})
+	}
+	return t
+}
+
+func newTaxResponsePB(year int, r TaxResponse) *taxpb.TaxResponse {
+	res := &taxpb.TaxResponse{
+		TaxYear:   int32(year),
+		Tax:       r.Tax,
+		TaxRefund: refund(r),
+		Summary: &taxpb.TaxSummary{
+			TotalIncome: r.summary.income,
+			Allowances:  r.summary.income - r.summary.netIncome,
+			NetIncome:   max(r.summary.netIncome, 0),
+			LevelTax:    r.summary.levelTax,
+			TaxPaid:     r.summary.taxPaid,
+			Credits:     r.summary.credits,
+		},
+	}
+
+	for _, l := range r.TaxLevels {
+		res.TaxLevels = append(res.TaxLevels, &taxpb.TaxLevel{Level: l.Level, Tax: l.Tax})
+	}
+
+	for _, c := range r.Credits {
+		res.Credits = append(res.Credits, &taxpb.Credit{CreditType: c.CreditType, Amount: c.Amount, Applied: c.Applied, Unused: c.Unused})
+	}
+
+	if s := r.Surcharge; s != nil {
+		res.Surcharge = &taxpb.Surcharge{Months: int32(s.Months), Amount: s.Amount, Penalty: s.Penalty, Total: s.Total}
+		for _, m := range s.Breakdown {
+			res.Surcharge.Breakdown = append(res.Surcharge.Breakdown, &taxpb.SurchargeMonth{Month: int32(m.Month), Amount: m.Amount})
+		}
+	}
+
+	for _, c := range r.Conversions {
+		res.Conversions = append(res.Conversions, &taxpb.Conversion{
+			Currency:     c.Currency,
+			ReceivedDate: c.ReceivedDate,
+			RateDate:     c.RateDate,
+			Rate:         c.Rate,
+			Amount:       c.Amount,
+			Wht:          c.WHT,
+			AmountThb:    c.AmountTHB,
+			WhtThb:       c.WHTTHB,
+		})
+	}
+	return res
+}
+
+func newProblemPB(err *problem.Error, p *i18n.Printer) *taxpb.Problem {
+	l := problem.Localize(err, p)
+	res := &taxpb.Problem{Code: l.Code, Message: l.Message}
+	for _, f := range l.Fields {
+		res.Errors = append(res.Errors, &taxpb.FieldError{Pointer: f.Pointer, Rule: f.Rule, Limit: f.Limit, Message: f.Message})
+	}
+	return res
+}
+
+func newDeductionPB(a *Allowances) *taxpb.Deduction {
+	return &taxpb.Deduction{
+		Id:             int32(a.ID),
+		Type:           a.Type,
+		InitAmount:     a.InitAmount,
+		MinAmount:      a.MinAmount,
+		MaxAmount:      a.MaxAmount,
+		LimitMaxAmount: a.LimitMaxAmount,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
+// calculate answers req the way TaxV2 does. It returns the status to
+// answer with when it fails.
+func (s *GRPCServer) calculate(ctx context.Context, req *taxpb.TaxRequest) (*taxpb.TaxResponse, int, error) {
+	year, err := taxYear(int(req.TaxYear))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	reqTax := newTaxRequest(req)
+	if err := s.validator.Validate(reqTax); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	res, status, err := s.handler.calculateValid(ctx, &reqTax)
+	if err != nil {
+		return nil, status, err
+	}
+	observeCalculation(res.summary.netIncome, res.Tax, res.TaxRefund != nil)
+	res.localize(i18n.ForGRPC(ctx))
+
+	return newTaxResponsePB(year, res), http.StatusOK, nil
+}
+
+func (s *GRPCServer) CalculateTax(ctx context.Context, req *taxpb.TaxRequest) (_ *taxpb.TaxResponse, err error) {
+	ctx, span := tracing.Start(ctx, "tax.GRPCServer.CalculateTax")
+	defer func() { tracing.End(span, err) }()
+
+	res, status, err := s.calculate(ctx, req)
+	if err != nil {
+		return nil, grpcErr(ctx, status, err)
+	}
+	return res, nil
+}
+
+// CalculateBatch counts every request against the row quota of the API key
+// of the stream.
+func (s *GRPCServer) CalculateBatch(stream taxpb.TaxService_CalculateBatchServer) error {
+	ctx := stream.Context()
+	p := i18n.ForGRPC(ctx)
+
+	for i := 0; ; i++ {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		err = apikey.ConsumeRowsContext(ctx, 1)
+		if errors.Is(err, apikey.ErrQuotaExceeded) {
+			rejectRows(rejectQuota, 1)
+			return grpcErr(ctx, http.StatusTooManyRequests, problem.New(http.StatusTooManyRequests, CodeRowQuotaExceeded, "Row quota exceeded"))
+		}
+		if err != nil {
+			status, err := storeStatus(ctx, err)
+			return grpcErr(ctx, status, err)
+		}
+
+		result := &taxpb.BatchResult{Index: int32(i)}
+		res, status, err := s.calculate(ctx, req)
+		if err != nil {
+			result.Result = &taxpb.BatchResult_Problem{Problem: newProblemPB(problem.From(err, status), p)}
+		} else {
+			result.Result = &taxpb.BatchResult_Response{Response: res}
+		}
+
+		if err := stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *GRPCServer) GetDeductions(ctx context.Context, _ *taxpb.GetDeductionsRequest) (*taxpb.GetDeductionsResponse, error) {
+	deductor, err := s.handler.deductors.Deductor(ctx)
+	if err != nil {
+		status, err := storeStatus(ctx, err)
+		return nil, grpcErr(ctx, status, err)
+	}
+
+	res := &taxpb.GetDeductionsResponse{}
+	for _, a := range deductor.allowances() {
+		res.Deductions = append(res.Deductions, newDeductionPB(&a))
+	}
+	return res, nil
+}
+
+func (s *GRPCServer) UpdateDeduction(ctx context.Context, req *taxpb.UpdateDeductionRequest) (_ *taxpb.UpdateDeductionResponse, err error) {
+	ctx, span := tracing.Start(ctx, "tax.GRPCServer.UpdateDeduction")
+	defer func() { tracing.End(span, err) }()
+
+	change := DeductionChange{Type: req.Type, Amount: req.Amount, Status: ChangePending}
+	if u := auth.UserFromContext(ctx); u != nil {
+		change.SubmittedBy = u.Username
+	}
+
+	status, err := validateChange(ctx, s.handler.store, &change)
+	if err != nil {
+		return nil, grpcErr(ctx, status, err)
+	}
+
+	if s.approvals != nil {
+		created, err := s.approvals.changes.CreateDeductionChange(ctx, change)
+		if err != nil {
+			status, err := storeStatus(ctx, err)
+			return nil, grpcErr(ctx, status, err)
+		}
+
+		observeDeductionChange(change.Type, "submitted")
+		return &taxpb.UpdateDeductionResponse{Result: &taxpb.UpdateDeductionResponse_Change{Change: &taxpb.DeductionChange{
+			Id:          int32(created.ID),
+			Type:        created.Type,
+			Amount:      created.Amount,
+			Status:      created.Status,
+			SubmittedBy: created.SubmittedBy,
+			CreatedAt:   created.CreatedAt,
+		}}}, nil
+	}
+
+	var updated *Allowances
+	switch change.Type {
+	case "personal":
+		updated, err = s.handler.store.UpdateInitPersonalAllowance(ctx, change.Amount)
+	case "k-receipt":
+		updated, err = s.handler.store.UpdateMaxAmountKreceipt(ctx, change.Amount)
+	}
+	if err != nil {
+		status, err := storeStatus(ctx, err)
+		return nil, grpcErr(ctx, status, err)
+	}
+	s.handler.deductors.Invalidate()
+	observeDeductionChange(change.Type, "updated")
+
+	return &taxpb.UpdateDeductionResponse{Result: &taxpb.UpdateDeductionResponse_Deduction{Deduction: newDeductionPB(updated)}}, nil
+}
@@ -0,0 +1,93 @@
+package tax
+
+import "github.com/Gitong23/assessment-tax/problem"
+
+const (
+	FilingSeparate = "separate"
+	FilingJoint    = "joint"
+)
+
+func (h *HouseholdRequest) validate() error {
+	err := h.Taxpayer.validate()
+	if err != nil {
+		return problem.Nest(err, "/taxpayer")
+	}
+
+	err = h.Spouse.validate()
+	if err != nil {
+		return problem.Nest(err, "/spouse")
+	}
+
+	if h.Taxpayer.period() != h.Spouse.period() {
+		return invalid(CodePeriodMismatch, "/spouse/period", "eqfield", "/taxpayer/period", "Spouses must file for the same period")
+	}
+
+	if h.Taxpayer.isLate() || h.Spouse.isLate() {
+		return problem.Invalid(CodeLateHousehold, "Late filing is not supported for household")
+	}
+	return nil
+}
+
+// joint combines both spouses' incomes, tax paid and credits into a single
+// filing. Allowances are left out as each spouse keeps their own.
+func (h *HouseholdRequest) joint() TaxRequest {
+	var credits []CreditReq
+	credits = append(credits, h.Taxpayer.Credits...)
+	credits = append(credits, h.Spouse.Credits...)
+
+	return TaxRequest{
+		TotalIncome: h.Taxpayer.TotalIncome + h.Spouse.TotalIncome,
+		WHT:         h.Taxpayer.WHT + h.Spouse.WHT,
+		Period:      h.Taxpayer.Period,
+		HalfYearTax: h.Taxpayer.HalfYearTax + h.Spouse.HalfYearTax,
+		Credits:     credits,
+	}
+}
+
+func newHouseholdOption(filing string, res ...TaxResponse) HouseholdOption {
+	o := HouseholdOption{Filing: filing, Results: res}
+	for _, r := range res {
+		o.Tax += r.Tax
+		if refund, ok := r.TaxRefund.(float64); ok {
+			o.TaxRefund += refund
+		}
+	}
+	return o
+}
+
+func (o *HouseholdOption) balance() float64 {
+	return o.Tax - o.TaxRefund
+}
+
+// household calculates the spouses' tax filing separately and jointly, and
+// recommends the option with the lower balance.
+func (d *Deductor) household(h HouseholdRequest) (*HouseholdResponse, error) {
+	taxpayer, err := d.calculate(h.Taxpayer)
+	if err != nil {
+		return nil, problem.Nest(err, "/taxpayer")
+	}
+
+	spouse, err := d.calculate(h.Spouse)
+	if err != nil {
+		return nil, problem.Nest(err, "/spouse")
+	}
+
+	joint := h.joint()
+	jointRes, err := joint.response(d.netIncome(h.Taxpayer) + d.netIncome(h.Spouse))
+	if err != nil {
+		return nil, err
+	}
+
+	separate := newHouseholdOption(FilingSeparate, taxpayer, spouse)
+	together := newHouseholdOption(FilingJoint, jointRes)
+
+	recommended := FilingSeparate
+	if together.balance() < separate.balance() {
+		recommended = FilingJoint
+	}
+
+	return &HouseholdResponse{
+		Options:     []HouseholdOption{separate, together},
+		Recommended: recommended,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package tax
+
+import (
+	"net/http"
+
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/labstack/echo/v4"
+)
+
+func isDryRun(c echo.Context) bool {
+	return c.QueryParam("dryRun") == "true"
+}
+
+// with returns a copy of d with the deduction of type t set to amount the
+// way an admin deduction change would set it.
+func (d *Deductor) with(t string, amount float64) (*Deductor, error) {
+	m := map[string]*Allowances{}
+	for k, a := range d.m {
+		copied := *a
+		m[k] = &copied
+	}
+
+	switch t {
+	case "personal":
+		m[t].InitAmount = amount
+	case "k-receipt":
+		m[t].MaxAmount = amount
+	default:
+		return nil, problem.Invalid(CodeInvalidDeductionType, "Invalid deduction type")
+	}
+	return &Deductor{m: m}, nil
+}
+
+// setting returns the amount a deduction change of type t sets.
+func (d *Deductor) setting(t string) float64 {
+	if t == "k-receipt" {
+		return d.max(t)
+	}
+	return d.initPer(t)
+}
+
+// bracket returns the index of the highest tax step netIncome reaches.
+func bracket(netIncome float64) int {
+	result := 0
+	for idx, s := range steps {
+		if netIncome > s.Min {
+			result = idx
+		}
+	}
+	return result
+}
+
+func newImpact(t string, current, proposed *Deductor, population []TaxRequest) *Impact {
+	impact := Impact{
+		Type:       t,
+		Current:    current.setting(t),
+		Proposed:   proposed.setting(t),
+		Population: len(population),
+	}
+
+	for _, tr := range population {
+		currentIncome := current.netIncome(tr)
+		proposedIncome := proposed.netIncome(tr)
+
+		currentTax := calLevelTax(currentIncome)
+		proposedTax := calLevelTax(proposedIncome)
+		impact.CurrentTax += currentTax
+		impact.ProposedTax += proposedTax
+
+		if proposedTax > currentTax {
+			impact.Increased++
+		}
+		if proposedTax < currentTax {
+			impact.Decreased++
+		}
+		if bracket(currentIncome) != bracket(proposedIncome) {
+			impact.BracketChanges++
+		}
+	}
+
+	impact.TotalChange = impact.ProposedTax - impact.CurrentTax
+	if impact.Population > 0 {
+		impact.AverageChange = impact.TotalChange / float64(impact.Population)
+	}
+	return &impact
+}
+
+// population returns the tax requests in the uploaded taxFile, or the
+// stored sample population when there is no file.
+func population(c echo.Context, s Storer) ([]TaxRequest, int, error) {
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["taxFile"]) == 0 {
+		samples, err := s.SampleTaxRequests(c.Request().Context())
+		if err != nil {
+			status, err := storeStatus(c.Request().Context(), err)
+			return nil, status, err
+		}
+		return samples, http.StatusOK, nil
+	}
+
+	files := form.File["taxFile"]
+	if !helper.IsFilesExt(".csv", files) {
+		return nil, http.StatusBadRequest, problem.Invalid(CodeUnsupportedFile, "Only CSV files are allowed")
+	}
+
+	src, err := OpenFormFile(files)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fileErr(c.Request().Context(), err)
+	}
+
+	taxesReq, err := fileSampleReq(src)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	err = checkMultiWht(taxesReq)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	return taxesReq, http.StatusOK, nil
+}
+
+// dryRun responds with the impact of setting the deduction of type t to
+// amount without changing it.
+func dryRun(c echo.Context, s Storer, t string, amount float64) error {
+	taxesReq, status, err := population(c, s)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	current, err := NewDeductor(c.Request().Context(), s)
+	if err != nil {
+		return storeErr(c, err)
+	}
+
+	err = current.checkMinMultiTaxReq(taxesReq)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	proposed, err := current.with(t, amount)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	return c.JSON(http.StatusOK, newImpact(t, current, proposed, taxesReq))
+}
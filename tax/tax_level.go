@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"math"
 
-	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/i18n"
 )
 
 type StepTax struct {
@@ -21,25 +21,30 @@ var steps = []StepTax{
 	{2000000, math.MaxFloat64, 0.35},
 }
 
-func taxLevel(netIncome float64) []TaxLevel {
-	var taxLevels []TaxLevel
-	for idx, s := range steps {
+// levelLabel names the income range of the step at idx in the language of
+// p.
+func levelLabel(idx int, p *i18n.Printer) string {
+	s := steps[idx]
+	if idx == 0 {
+		return fmt.Sprintf("0 - %s", p.Number(s.Max))
+	}
 
-		var level string
-		if idx == 0 {
-			level = fmt.Sprintf("0 - %s", helper.Comma(s.Max))
-		}
+	if idx == len(steps)-1 {
+		return p.Sprintf("%s and above", p.Number(s.Min))
+	}
 
-		if idx == len(steps)-1 {
-			level = fmt.Sprintf("%s ขึ้นไป", helper.Comma(s.Min))
-		}
+	return fmt.Sprintf("%s - %s", p.Number(s.Min+1), p.Number(s.Max))
+}
 
-		if idx > 0 && idx < len(steps)-1 {
-			level = fmt.Sprintf("%s - %s", helper.Comma(s.Min+1), helper.Comma(s.Max))
-		}
+// taxLevel labels the levels in the default language. Handlers relabel
+// them in the language of the request with localize.
+func taxLevel(netIncome float64) []TaxLevel {
+	p := i18n.NewPrinter(i18n.Default)
 
+	var taxLevels []TaxLevel
+	for idx, s := range steps {
 		taxLevels = append(taxLevels, TaxLevel{
-			Level: level,
+			Level: levelLabel(idx, p),
 			Tax:   s.taxStep(netIncome),
 		})
 		netIncome -= s.Max - s.Min
@@ -47,6 +52,21 @@ func taxLevel(netIncome float64) []TaxLevel {
 	return taxLevels
 }
 
+// localize relabels the tax levels of r in the language of p.
+func (r *TaxResponse) localize(p *i18n.Printer) {
+	for idx := range r.TaxLevels {
+		r.TaxLevels[idx].Level = levelLabel(idx, p)
+	}
+}
+
+func (r *HouseholdResponse) localize(p *i18n.Printer) {
+	for _, o := range r.Options {
+		for idx := range o.Results {
+			o.Results[idx].localize(p)
+		}
+	}
+}
+
 func calLevelTax(netIncome float64) float64 {
 	result := 0.0
 	for _, s := range steps {
@@ -88,6 +108,28 @@ func NewTaxResponse(wht float64, income float64) TaxResponse {
 	}
 }
 
+// response calculates the tax on netIncome and applies the request's credits
+// and late filing surcharge.
+func (t *TaxRequest) response(netIncome float64) (TaxResponse, error) {
+	credits, applied := newCredits(t, netIncome)
+	res := NewTaxResponse(t.credit()+applied, netIncome)
+	res.Credits = credits
+	res.summary = summary{
+		income:    t.TotalIncome + t.grossDividend(),
+		netIncome: netIncome,
+		levelTax:  calLevelTax(netIncome),
+		taxPaid:   t.credit(),
+		credits:   applied,
+	}
+
+	var err error
+	res.Surcharge, err = newSurcharge(t, res.Tax)
+	if err != nil {
+		return TaxResponse{}, err
+	}
+	return res, nil
+}
+
 func NewTaxUpload(taxReq TaxRequest, income float64) TaxUpload {
 	tax := calLevelTax(income)
 
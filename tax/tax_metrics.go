@@ -0,0 +1,39 @@
+package tax
+
+import (
+	"fmt"
+
+	"github.com/Gitong23/assessment-tax/metrics"
+)
+
+// Reasons uploaded CSV rows are rejected for.
+const (
+	rejectParse     = "parse"
+	rejectWHT       = "wht"
+	rejectAllowance = "allowance"
+	rejectQuota     = "quota"
+)
+
+// observeCalculation counts a calculation by the highest bracket netIncome
+// reaches and by its result.
+func observeCalculation(netIncome float64, tax float64, refund bool) {
+	rate := fmt.Sprintf("%g%%", steps[bracket(netIncome)].Rate*100)
+	metrics.Calculations.WithLabelValues(rate).Inc()
+
+	result := metrics.ResultNone
+	if refund {
+		result = metrics.ResultRefund
+	}
+	if tax > 0 {
+		result = metrics.ResultPayment
+	}
+	metrics.CalculationResults.WithLabelValues(result).Inc()
+}
+
+func rejectRows(reason string, rows int) {
+	metrics.CSVRowsRejected.WithLabelValues(reason).Add(float64(rows))
+}
+
+func observeDeductionChange(t string, action string) {
+	metrics.DeductionChanges.WithLabelValues(t, action).Inc()
+}
@@ -0,0 +1,41 @@
+package tax
+
+const (
+	PeriodAnnual   = "annual"
+	PeriodHalfYear = "half-year"
+)
+
+// halfYearRate scales the allowance amounts for a half-year (PND94) filing.
+const halfYearRate = 0.5
+
+// annualOnly allowances can't be claimed in a half-year filing.
+var annualOnly = map[string]bool{
+	"k-receipt": true,
+}
+
+func (t *TaxRequest) period() string {
+	if t.Period == "" {
+		return PeriodAnnual
+	}
+	return t.Period
+}
+
+func (t *TaxRequest) validatePeriod() error {
+	if t.period() != PeriodAnnual && t.period() != PeriodHalfYear {
+		return invalid(CodeInvalidPeriod, "/period", "oneof", PeriodAnnual+" "+PeriodHalfYear, "Invalid period value")
+	}
+
+	if t.HalfYearTax < 0 {
+		return invalid(CodeInvalidHalfYearTax, "/halfYearTax", "gte", "0", "Invalid half-year tax value")
+	}
+
+	if t.period() == PeriodHalfYear && t.HalfYearTax > 0 {
+		return invalid(CodeInvalidHalfYearTax, "/halfYearTax", "eq", "0", "Half-year tax can only be credited in annual period")
+	}
+	return nil
+}
+
+// credit is the tax already paid in advance for the period.
+func (t *TaxRequest) credit() float64 {
+	return t.WHT + t.HalfYearTax
+}
@@ -0,0 +1,53 @@
+package tax
+
+import (
+	"strconv"
+
+	"github.com/Gitong23/assessment-tax/problem"
+)
+
+// Codes of the problems the tax handlers respond with. They are part of the
+// API: clients match on them, so they never change once released.
+const (
+	CodeInvalidForm            = "invalid_form"
+	CodeUnsupportedFile        = "unsupported_file_type"
+	CodeInvalidCSV             = "invalid_csv"
+	CodeInvalidCSVHeader       = "invalid_csv_header"
+	CodeInvalidNumber          = "invalid_number"
+	CodeInvalidDate            = "invalid_date"
+	CodeInvalidCurrency        = "invalid_currency"
+	CodeInvalidRate            = "invalid_rate"
+	CodeNoExchangeRate         = "exchange_rate_not_found"
+	CodeInvalidWHT             = "invalid_wht"
+	CodeInvalidIncomeAmount    = "invalid_income_amount"
+	CodeInvalidAllowanceAmount = "invalid_allowance_amount"
+	CodeInvalidCredit          = "invalid_credit"
+	CodeForeignIncome          = "foreign_income_exceeds_total"
+	CodeInvalidPeriod          = "invalid_period"
+	CodeInvalidTaxYear         = "invalid_tax_year"
+	CodeInvalidHalfYearTax     = "invalid_half_year_tax"
+	CodeInvalidPenalty         = "invalid_penalty"
+	CodePeriodMismatch         = "period_mismatch"
+	CodeLateHousehold          = "late_filing_unsupported"
+	CodeInvalidDeductionType   = "invalid_deduction_type"
+	CodeInvalidDeduction       = "invalid_deduction_amount"
+	CodeRowQuotaExceeded       = "row_quota_exceeded"
+	CodeInvalidStatus          = "invalid_status"
+	CodeInvalidChangeID        = "invalid_change_id"
+	CodeChangeNotFound         = "change_not_found"
+	CodeChangeDecided          = "change_already_decided"
+	CodeSelfApproval           = "self_approval"
+	CodeChangeNotPending       = "change_not_pending"
+	CodeInvalidComment         = "invalid_comment"
+)
+
+// invalid returns a bad request for the single field at pointer, with the
+// message of the field as the message of the problem.
+func invalid(code, pointer, rule, limit, message string, args ...interface{}) error {
+	return problem.Invalid(code, message, problem.Field(pointer, rule, limit, message, args...)).With(args...)
+}
+
+// limit formats an amount the way it's written in a request.
+func limit(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
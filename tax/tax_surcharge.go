@@ -0,0 +1,113 @@
+package tax
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// surchargeRate is charged for each month or fraction of a month the tax
+	// is paid after the due date, up to the amount of the tax itself.
+	surchargeRate = 0.015
+
+	// lateFilingFine is the fixed fine for filing after the due date.
+	lateFilingFine = 2000.0
+
+	// assessmentPenaltyRate is the penalty on the tax found by an additional
+	// assessment.
+	assessmentPenaltyRate = 1.0
+)
+
+const (
+	PenaltyLateFiling = "late-filing"
+	PenaltyAssessment = "assessment"
+)
+
+type SurchargeMonth struct {
+	Month  int     `json:"month"`
+	Amount float64 `json:"amount"`
+}
+
+type Surcharge struct {
+	Months    int              `json:"months"`
+	Amount    float64          `json:"amount"`
+	Penalty   float64          `json:"penalty"`
+	Total     float64          `json:"total"`
+	Breakdown []SurchargeMonth `json:"breakdown"`
+}
+
+func parseDate(s string) (time.Time, error) {
+	return time.Parse(dateLayout, s)
+}
+
+// addMonths returns t months later, on the last day of that month when it
+// is shorter than the day of t, where AddDate would roll into the next one.
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+func lateMonths(due, filing time.Time) int {
+	months := 0
+	for addMonths(due, months).Before(filing) {
+		months++
+	}
+	return months
+}
+
+func penalty(p string, tax float64) (float64, error) {
+	switch p {
+	case "":
+		return 0, nil
+	case PenaltyLateFiling:
+		return lateFilingFine, nil
+	case PenaltyAssessment:
+		return tax * assessmentPenaltyRate, nil
+	}
+	return 0, invalid(CodeInvalidPenalty, "/penalty", "oneof", PenaltyLateFiling+" "+PenaltyAssessment, "Invalid penalty value")
+}
+
+func (t *TaxRequest) isLate() bool {
+	return t.FilingDate != "" || t.DueDate != "" || t.Penalty != ""
+}
+
+// newSurcharge computes the surcharge and penalty on the tax payable. It
+// returns nil when the request has no filing details.
+func newSurcharge(t *TaxRequest, tax float64) (*Surcharge, error) {
+	if !t.isLate() {
+		return nil, nil
+	}
+
+	s := Surcharge{Breakdown: []SurchargeMonth{}}
+	if t.FilingDate != "" || t.DueDate != "" {
+		due, err := parseDate(t.DueDate)
+		if err != nil {
+			return nil, invalid(CodeInvalidDate, "/dueDate", "date", dateLayout, "Invalid due date")
+		}
+
+		filing, err := parseDate(t.FilingDate)
+		if err != nil {
+			return nil, invalid(CodeInvalidDate, "/filingDate", "date", dateLayout, "Invalid filing date")
+		}
+
+		s.Months = lateMonths(due, filing)
+	}
+
+	for m := 1; m <= s.Months && s.Amount < tax; m++ {
+		amount := math.Min(tax*surchargeRate, tax-s.Amount)
+		s.Breakdown = append(s.Breakdown, SurchargeMonth{Month: m, Amount: amount})
+		s.Amount += amount
+	}
+
+	p, err := penalty(t.Penalty, tax)
+	if err != nil {
+		return nil, err
+	}
+	s.Penalty = p
+	s.Total = tax + s.Amount + s.Penalty
+
+	return &s, nil
+}
@@ -2,51 +2,148 @@ package tax
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
+	"math"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Gitong23/assessment-tax/auth"
+	"github.com/Gitong23/assessment-tax/helper"
+	"github.com/Gitong23/assessment-tax/i18n"
+	"github.com/Gitong23/assessment-tax/logger"
+	"github.com/Gitong23/assessment-tax/metrics"
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/taxpb"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 type Stub struct {
 	personalAllowance *Allowances
 	donationAllowance *Allowances
 	kreceiptAllowance *Allowances
+	exchangeRates     []ExchangeRate
+	changes           []DeductionChange
+	samples           []TaxRequest
 	adminUsername     string
 	adminPassword     string
 	err               error
 }
 
-func (s *Stub) PersonalAllowance() (*Allowances, error) {
+func (s *Stub) PersonalAllowance(ctx context.Context) (*Allowances, error) {
 	return s.personalAllowance, s.err
 }
 
-func (s *Stub) DonationAllowance() (*Allowances, error) {
+func (s *Stub) DonationAllowance(ctx context.Context) (*Allowances, error) {
 	return s.donationAllowance, s.err
 }
 
-func (s *Stub) KreceiptAllowance() (*Allowances, error) {
+func (s *Stub) KreceiptAllowance(ctx context.Context) (*Allowances, error) {
 	return s.kreceiptAllowance, s.err
 }
 
-func (s *Stub) UpdateInitPersonalAllowance(amount float64) (*Allowances, error) {
+func (s *Stub) UpdateInitPersonalAllowance(ctx context.Context, amount float64) (*Allowances, error) {
 	s.personalAllowance.InitAmount = amount
 	return s.personalAllowance, s.err
 }
 
-func (s *Stub) UpdateMaxAmountKreceipt(amount float64) (*Allowances, error) {
+func (s *Stub) UpdateMaxAmountKreceipt(ctx context.Context, amount float64) (*Allowances, error) {
 	s.kreceiptAllowance.MaxAmount = amount
 	return s.kreceiptAllowance, s.err
 }
 
+func (s *Stub) ExchangeRate(ctx context.Context, currency string, date string) (*ExchangeRate, error) {
+	stale, err := StaleRateDate(date)
+	if err != nil {
+		return nil, err
+	}
+
+	var rate *ExchangeRate
+	for i, r := range s.exchangeRates {
+		if r.Currency == currency && r.Date <= date && r.Date > stale && (rate == nil || r.Date > rate.Date) {
+			rate = &s.exchangeRates[i]
+		}
+	}
+	return rate, s.err
+}
+
+func (s *Stub) UpdateExchangeRates(ctx context.Context, rates []ExchangeRate) ([]ExchangeRate, error) {
+	s.exchangeRates = append(s.exchangeRates, rates...)
+	return rates, s.err
+}
+
+func (s *Stub) SampleTaxRequests(ctx context.Context) ([]TaxRequest, error) {
+	return s.samples, s.err
+}
+
+func (s *Stub) UpdateSampleTaxRequests(ctx context.Context, samples []TaxRequest) error {
+	s.samples = samples
+	return s.err
+}
+
+func (s *Stub) CreateDeductionChange(ctx context.Context, change DeductionChange) (*DeductionChange, error) {
+	change.ID = len(s.changes) + 1
+	s.changes = append(s.changes, change)
+	return &change, s.err
+}
+
+func (s *Stub) DeductionChanges(ctx context.Context, status string) ([]DeductionChange, error) {
+	var changes []DeductionChange
+	for _, c := range s.changes {
+		if status == "" || c.Status == status {
+			changes = append(changes, c)
+		}
+	}
+	return changes, s.err
+}
+
+func (s *Stub) DeductionChange(ctx context.Context, id int) (*DeductionChange, error) {
+	if id < 1 || id > len(s.changes) {
+		return nil, s.err
+	}
+	change := s.changes[id-1]
+	return &change, s.err
+}
+
+func (s *Stub) DecideDeductionChange(ctx context.Context, id int, status string, by string) (*DeductionChange, error) {
+	change := &s.changes[id-1]
+	change.Status = status
+	change.DecidedBy = by
+
+	if status == ChangeApproved && change.Type == "personal" {
+		s.personalAllowance.InitAmount = change.Amount
+	}
+	if status == ChangeApproved && change.Type == "k-receipt" {
+		s.kreceiptAllowance.MaxAmount = change.Amount
+	}
+	return s.DeductionChange(ctx, id)
+}
+
+func (s *Stub) AddDeductionChangeComment(ctx context.Context, id int, author string, comment string) (*ChangeComment, error) {
+	cc := ChangeComment{ID: len(s.changes[id-1].Comments) + 1, Author: author, Comment: comment}
+	s.changes[id-1].Comments = append(s.changes[id-1].Comments, cc)
+	return &cc, s.err
+}
+
 func NewEcho() *echo.Echo {
 	e := echo.New()
 	e.Validator = NewValidator()
@@ -288,6 +385,305 @@ func TestCalTax(t *testing.T) {
 			wantRes:  TaxResponse{Tax: 0.0, TaxLevels: taxLevel(330000.0), TaxRefund: 2000.0},
 			wantHttp: http.StatusOK,
 		},
+		{
+			name: "Half-year income 300k allowance 0 tax should be 12k",
+			reqBody: TaxRequest{
+				TotalIncome: 300000.0,
+				WHT:         0.0,
+				Period:      PeriodHalfYear,
+			},
+			wantRes:  TaxResponse{Tax: 12000.0, TaxLevels: taxLevel(270000.0)},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Half-year allowance donation 200k k-receipt 10k tax should be 7k",
+			reqBody: TaxRequest{
+				TotalIncome: 300000.0,
+				WHT:         0.0,
+				Period:      PeriodHalfYear,
+				Allowances: []AllowanceReq{
+					{
+						AllowanceType: "donation",
+						Amount:        200000.0,
+					},
+					{
+						AllowanceType: "k-receipt",
+						Amount:        10000.0,
+					},
+				},
+			},
+			wantRes:  TaxResponse{Tax: 7000.0, TaxLevels: taxLevel(220000.0)},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Annual income 500k half-year tax 12k tax should be 17k",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				WHT:         0.0,
+				HalfYearTax: 12000.0,
+			},
+			wantRes:  TaxResponse{Tax: 17000.0, TaxLevels: taxLevel(440000.0)},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Annual income 500k wht 20k half-year tax 12k tax should get refund 3k",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				WHT:         20000.0,
+				Period:      PeriodAnnual,
+				HalfYearTax: 12000.0,
+			},
+			wantRes:  TaxResponse{Tax: 0.0, TaxLevels: taxLevel(440000.0), TaxRefund: 3000.0},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Half-year tax can't be credited in half-year period",
+			reqBody: TaxRequest{
+				TotalIncome: 300000.0,
+				Period:      PeriodHalfYear,
+				HalfYearTax: 1000.0,
+			},
+			wantRes:  TaxResponse{Tax: 0.0},
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid period",
+			reqBody: TaxRequest{
+				TotalIncome: 300000.0,
+				Period:      "quarter",
+			},
+			wantRes:  TaxResponse{Tax: 0.0},
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name: "Income 500k filed 3 months late should add surcharge 1,305",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				DueDate:     "2024-04-08",
+				FilingDate:  "2024-06-10",
+			},
+			wantRes: TaxResponse{
+				Tax:       29000.0,
+				TaxLevels: taxLevel(440000.0),
+				Surcharge: &Surcharge{
+					Months: 3,
+					Amount: 1305.0,
+					Total:  30305.0,
+					Breakdown: []SurchargeMonth{
+						{Month: 1, Amount: 435.0},
+						{Month: 2, Amount: 435.0},
+						{Month: 3, Amount: 435.0},
+					},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Due Jan 31 filed Mar 2 is 2 months late past the end of February",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				DueDate:     "2024-01-31",
+				FilingDate:  "2024-03-02",
+			},
+			wantRes: TaxResponse{
+				Tax:       29000.0,
+				TaxLevels: taxLevel(440000.0),
+				Surcharge: &Surcharge{
+					Months: 2,
+					Amount: 870.0,
+					Total:  29870.0,
+					Breakdown: []SurchargeMonth{
+						{Month: 1, Amount: 435.0},
+						{Month: 2, Amount: 435.0},
+					},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Due Mar 31 filed May 1 is 2 months late past the end of April",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				DueDate:     "2024-03-31",
+				FilingDate:  "2024-05-01",
+			},
+			wantRes: TaxResponse{
+				Tax:       29000.0,
+				TaxLevels: taxLevel(440000.0),
+				Surcharge: &Surcharge{
+					Months: 2,
+					Amount: 870.0,
+					Total:  29870.0,
+					Breakdown: []SurchargeMonth{
+						{Month: 1, Amount: 435.0},
+						{Month: 2, Amount: 435.0},
+					},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Filed on time with assessment penalty",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				WHT:         25000.0,
+				DueDate:     "2024-04-08",
+				FilingDate:  "2024-04-08",
+				Penalty:     PenaltyAssessment,
+			},
+			wantRes: TaxResponse{
+				Tax:       4000.0,
+				TaxLevels: taxLevel(440000.0),
+				Surcharge: &Surcharge{Penalty: 4000.0, Total: 8000.0, Breakdown: []SurchargeMonth{}},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Income 500k dividend 100k at 20% tax should be 19,750",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				Credits: []CreditReq{
+					{
+						CreditType: CreditDividend,
+						Amount:     100000.0,
+						Rate:       0.20,
+					},
+				},
+			},
+			wantRes: TaxResponse{
+				Tax:       19750.0,
+				TaxLevels: taxLevel(565000.0),
+				Credits: []CreditRes{
+					{CreditType: CreditDividend, Amount: 25000.0, Applied: 25000.0, Unused: 0.0},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Foreign tax credit is limited to Thai tax on foreign income",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				Credits: []CreditReq{
+					{
+						CreditType: CreditForeign,
+						Amount:     100000.0,
+						TaxPaid:    10000.0,
+					},
+				},
+			},
+			wantRes: TaxResponse{
+				Tax:       23200.0,
+				TaxLevels: taxLevel(440000.0),
+				Credits: []CreditRes{
+					{CreditType: CreditForeign, Amount: 10000.0, Applied: 5800.0, Unused: 4200.0},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Foreign tax credit without income applies nothing",
+			reqBody: TaxRequest{
+				TotalIncome: 0.0,
+				Credits: []CreditReq{
+					{
+						CreditType: CreditForeign,
+						Amount:     0.0,
+						TaxPaid:    100.0,
+					},
+				},
+			},
+			wantRes: TaxResponse{
+				Tax:       0.0,
+				TaxLevels: taxLevel(0.0),
+				Credits: []CreditRes{
+					{CreditType: CreditForeign, Amount: 100.0, Applied: 0.0, Unused: 100.0},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Invalid credit type",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				Credits: []CreditReq{
+					{
+						CreditType: "rmf",
+						Amount:     100000.0,
+					},
+				},
+			},
+			wantRes:  TaxResponse{Tax: 0.0},
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name: "Income 10k USD wht 500 USD should get refund 3,500",
+			reqBody: TaxRequest{
+				Incomes: []IncomeReq{
+					{
+						Amount:       10000.0,
+						WHT:          500.0,
+						Currency:     "USD",
+						ReceivedDate: "2024-01-15",
+					},
+				},
+			},
+			wantRes: TaxResponse{
+				Tax:       0.0,
+				TaxRefund: 3500.0,
+				TaxLevels: taxLevel(290000.0),
+				Conversions: []Conversion{
+					{
+						Currency:     "USD",
+						ReceivedDate: "2024-01-15",
+						RateDate:     "2024-01-12",
+						Rate:         35.0,
+						Amount:       10000.0,
+						WHT:          500.0,
+						AmountTHB:    350000.0,
+						WHTTHB:       17500.0,
+					},
+				},
+			},
+			wantHttp: http.StatusOK,
+		},
+		{
+			name: "Income with only an exchange rate older than a week",
+			reqBody: TaxRequest{
+				Incomes: []IncomeReq{
+					{
+						Amount:       10000.0,
+						Currency:     "USD",
+						ReceivedDate: "2024-02-15",
+					},
+				},
+			},
+			wantRes:  TaxResponse{Tax: 0.0},
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name: "Income without exchange rate",
+			reqBody: TaxRequest{
+				Incomes: []IncomeReq{
+					{
+						Amount:       10000.0,
+						Currency:     "EUR",
+						ReceivedDate: "2024-01-15",
+					},
+				},
+			},
+			wantRes:  TaxResponse{Tax: 0.0},
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid filing date",
+			reqBody: TaxRequest{
+				TotalIncome: 500000.0,
+				DueDate:     "2024-04-08",
+				FilingDate:  "10/06/2024",
+			},
+			wantRes:  TaxResponse{Tax: 0.0},
+			wantHttp: http.StatusBadRequest,
+		},
 	}
 
 	stubTax := &Stub{
@@ -318,6 +714,11 @@ func TestCalTax(t *testing.T) {
 			LimitMaxAmount: 100000.0,
 			CreatedAt:      "2024-04-22",
 		},
+		exchangeRates: []ExchangeRate{
+			{Date: "2024-01-02", Currency: "USD", Rate: 34.0},
+			{Date: "2024-01-12", Currency: "USD", Rate: 35.0},
+			{Date: "2024-01-16", Currency: "USD", Rate: 36.0},
+		},
 		err: nil,
 	}
 
@@ -357,56 +758,74 @@ func TestCalTax(t *testing.T) {
 	}
 }
 
-func TestUpdatePersonalDeduction(t *testing.T) {
+func TestSurchargeCapped(t *testing.T) {
+	taxReq := TaxRequest{
+		TotalIncome: 500000.0,
+		DueDate:     "2024-04-08",
+		FilingDate:  "2031-06-10",
+		Penalty:     PenaltyLateFiling,
+	}
+
+	got, err := newSurcharge(&taxReq, 29000.0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.Months != 87 {
+		t.Errorf("expected 87 months but got %d", got.Months)
+	}
+
+	if len(got.Breakdown) != 67 {
+		t.Errorf("expected 67 surcharged months but got %d", len(got.Breakdown))
+	}
+
+	if math.Abs(got.Amount-29000.0) > 0.01 {
+		t.Errorf("expected surcharge capped at 29000 but got %v", got.Amount)
+	}
+
+	if math.Abs(got.Total-60000.0) > 0.01 {
+		t.Errorf("expected total 60000 but got %v", got.Total)
+	}
+}
+
+func TestHousehold(t *testing.T) {
 	tests := []struct {
-		name     string
-		username string
-		password string
-		httpWant int
-		reqBody  DeductionReq
-		wantRes  InitPersonalDeductRes
+		name            string
+		reqBody         HouseholdRequest
+		wantHttp        int
+		wantSeparate    float64
+		wantJoint       float64
+		wantRecommended string
 	}{
 		{
-			name:     "Wrong username password",
-			username: "user",
-			password: "888",
-			httpWant: http.StatusUnauthorized,
-			reqBody: DeductionReq{
-				Amount: 70000,
-			},
-			wantRes: InitPersonalDeductRes{},
-		},
-		{
-			name:     "Amount 70 k",
-			username: "adminTax",
-			password: "admin!",
-			httpWant: http.StatusOK,
-			reqBody: DeductionReq{
-				Amount: 70000,
-			},
-			wantRes: InitPersonalDeductRes{
-				PersonalDeduction: 70000,
+			name: "Spouse without income should file jointly",
+			reqBody: HouseholdRequest{
+				Taxpayer: TaxRequest{TotalIncome: 500000.0},
+				Spouse:   TaxRequest{TotalIncome: 0.0},
 			},
+			wantHttp:        http.StatusOK,
+			wantSeparate:    29000.0,
+			wantJoint:       23000.0,
+			wantRecommended: FilingJoint,
 		},
 		{
-			name:     "Exceed Max Amount",
-			username: "adminTax",
-			password: "admin!",
-			httpWant: http.StatusBadRequest,
-			reqBody: DeductionReq{
-				Amount: 700000,
+			name: "Spouses with high income should file separately",
+			reqBody: HouseholdRequest{
+				Taxpayer: TaxRequest{TotalIncome: 1200000.0},
+				Spouse:   TaxRequest{TotalIncome: 1200000.0},
 			},
-			wantRes: InitPersonalDeductRes{},
+			wantHttp:        http.StatusOK,
+			wantSeparate:    276000.0,
+			wantJoint:       408000.0,
+			wantRecommended: FilingSeparate,
 		},
 		{
-			name:     "Lower than Min Amount",
-			username: "adminTax",
-			password: "admin!",
-			httpWant: http.StatusBadRequest,
-			reqBody: DeductionReq{
-				Amount: -50.0,
+			name: "Spouses must file for the same period",
+			reqBody: HouseholdRequest{
+				Taxpayer: TaxRequest{TotalIncome: 500000.0},
+				Spouse:   TaxRequest{TotalIncome: 300000.0, Period: PeriodHalfYear},
 			},
-			wantRes: InitPersonalDeductRes{},
+			wantHttp: http.StatusBadRequest,
 		},
 	}
 
@@ -415,13 +834,145 @@ func TestUpdatePersonalDeduction(t *testing.T) {
 			ID:             1,
 			Type:           "personal",
 			InitAmount:     60000,
+			MinAmount:      10000.0,
+			MaxAmount:      100000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		donationAllowance: &Allowances{
+			ID:             2,
+			Type:           "donation",
+			InitAmount:     0,
 			MinAmount:      0,
 			MaxAmount:      100000.0,
 			LimitMaxAmount: 100000.0,
 			CreatedAt:      "2024-04-22",
 		},
-		adminUsername: "adminTax",
-		adminPassword: "admin!",
+		kreceiptAllowance: &Allowances{
+			ID:             3,
+			Type:           "k-receipt",
+			InitAmount:     0,
+			MinAmount:      0,
+			MaxAmount:      50000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		err: nil,
+	}
+
+	e := NewEcho()
+	e.POST("/tax/calculations/household", NewHandler(stub).Household)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBodyStr, err := json.Marshal(tt.reqBody)
+			if err != nil {
+				t.Errorf("error marshalling json: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/tax/calculations/household", strings.NewReader(string(reqBodyStr)))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var got HouseholdResponse
+			err = json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Errorf("error unmarshalling json: %v", err)
+			}
+
+			if len(got.Options) != 2 {
+				t.Fatalf("expected 2 filing options but got %d", len(got.Options))
+			}
+
+			if got.Options[0].Tax != tt.wantSeparate {
+				t.Errorf("expected separate tax %v but got %v", tt.wantSeparate, got.Options[0].Tax)
+			}
+
+			if got.Options[1].Tax != tt.wantJoint {
+				t.Errorf("expected joint tax %v but got %v", tt.wantJoint, got.Options[1].Tax)
+			}
+
+			if got.Recommended != tt.wantRecommended {
+				t.Errorf("expected %s but got %s", tt.wantRecommended, got.Recommended)
+			}
+		})
+	}
+}
+
+func TestUpdatePersonalDeduction(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		password string
+		httpWant int
+		reqBody  DeductionReq
+		wantRes  InitPersonalDeductRes
+	}{
+		{
+			name:     "Wrong username password",
+			username: "user",
+			password: "888",
+			httpWant: http.StatusUnauthorized,
+			reqBody: DeductionReq{
+				Amount: 70000,
+			},
+			wantRes: InitPersonalDeductRes{},
+		},
+		{
+			name:     "Amount 70 k",
+			username: "adminTax",
+			password: "admin!",
+			httpWant: http.StatusOK,
+			reqBody: DeductionReq{
+				Amount: 70000,
+			},
+			wantRes: InitPersonalDeductRes{
+				PersonalDeduction: 70000,
+			},
+		},
+		{
+			name:     "Exceed Max Amount",
+			username: "adminTax",
+			password: "admin!",
+			httpWant: http.StatusBadRequest,
+			reqBody: DeductionReq{
+				Amount: 700000,
+			},
+			wantRes: InitPersonalDeductRes{},
+		},
+		{
+			name:     "Lower than Min Amount",
+			username: "adminTax",
+			password: "admin!",
+			httpWant: http.StatusBadRequest,
+			reqBody: DeductionReq{
+				Amount: -50.0,
+			},
+			wantRes: InitPersonalDeductRes{},
+		},
+	}
+
+	stub := &Stub{
+		personalAllowance: &Allowances{
+			ID:             1,
+			Type:           "personal",
+			InitAmount:     60000,
+			MinAmount:      0,
+			MaxAmount:      100000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		adminUsername: "adminTax",
+		adminPassword: "admin!",
 		err:           nil,
 	}
 
@@ -433,42 +984,578 @@ func TestUpdatePersonalDeduction(t *testing.T) {
 		return false, nil
 	}))
 
-	e.POST("/admin/deductions/personal", NewHandler(stub).UpdateInitPersonalDeduct)
+	e.POST("/admin/deductions/personal", NewHandler(stub).UpdateInitPersonalDeduct)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			reqBodyStr, err := json.Marshal(tt.reqBody)
+			if err != nil {
+				t.Errorf("error marshalling json: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/deductions/personal", strings.NewReader(string(reqBodyStr)))
+			req.SetBasicAuth(tt.username, tt.password)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetPath("/admin/deductions/personal")
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.httpWant {
+				t.Errorf("expected status code %d but got %d", tt.httpWant, rec.Code)
+			}
+
+			var got InitPersonalDeductRes
+			err = json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Errorf("error unmarshalling json: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("expected %v but got %v", tt.wantRes, got)
+			}
+		})
+	}
+}
+
+func TestDeductionApproval(t *testing.T) {
+	steps := []struct {
+		name     string
+		user     string
+		path     string
+		reqBody  string
+		wantHttp int
+	}{
+		{
+			name:     "Submit personal deduction 70k",
+			user:     "alice",
+			path:     "/admin/deductions/personal",
+			reqBody:  `{"amount": 70000}`,
+			wantHttp: http.StatusAccepted,
+		},
+		{
+			name:     "Submitter can't approve their own change",
+			user:     "alice",
+			path:     "/admin/deductions/changes/1/approve",
+			wantHttp: http.StatusForbidden,
+		},
+		{
+			name:     "Another admin approves the change",
+			user:     "bob",
+			path:     "/admin/deductions/changes/1/approve",
+			wantHttp: http.StatusOK,
+		},
+		{
+			name:     "Approved change can't be approved again",
+			user:     "bob",
+			path:     "/admin/deductions/changes/1/approve",
+			wantHttp: http.StatusConflict,
+		},
+		{
+			name:     "Submit k-receipt deduction exceeding limit",
+			user:     "alice",
+			path:     "/admin/deductions/k-receipt",
+			reqBody:  `{"amount": 700000}`,
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name:     "Submit k-receipt deduction 70k",
+			user:     "alice",
+			path:     "/admin/deductions/k-receipt",
+			reqBody:  `{"amount": 70000}`,
+			wantHttp: http.StatusAccepted,
+		},
+		{
+			name:     "Comment on the change",
+			user:     "bob",
+			path:     "/admin/deductions/changes/2/comments",
+			reqBody:  `{"comment": "k-receipt cap is 50k this year"}`,
+			wantHttp: http.StatusCreated,
+		},
+		{
+			name:     "Another admin rejects the change",
+			user:     "bob",
+			path:     "/admin/deductions/changes/2/reject",
+			wantHttp: http.StatusOK,
+		},
+		{
+			name:     "Change not found",
+			user:     "bob",
+			path:     "/admin/deductions/changes/3/approve",
+			wantHttp: http.StatusNotFound,
+		},
+	}
+
+	stub := &Stub{
+		personalAllowance: &Allowances{
+			ID:             1,
+			Type:           "personal",
+			InitAmount:     60000,
+			MinAmount:      10000.0,
+			MaxAmount:      100000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		kreceiptAllowance: &Allowances{
+			ID:             3,
+			Type:           "k-receipt",
+			InitAmount:     0,
+			MinAmount:      0,
+			MaxAmount:      50000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		err: nil,
+	}
+
+	e := NewEcho()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth.SetUser(c, &auth.User{Username: c.Request().Header.Get("X-User"), Role: auth.RoleApprover})
+			return next(c)
+		}
+	})
+
+	h := NewApprovalHandler(stub, stub, NewDeductorCache(stub))
+	e.POST("/admin/deductions/personal", h.SubmitPersonalDeduct)
+	e.POST("/admin/deductions/k-receipt", h.SubmitKreceiptDeduct)
+	e.POST("/admin/deductions/changes/:id/approve", h.Approve)
+	e.POST("/admin/deductions/changes/:id/reject", h.Reject)
+	e.POST("/admin/deductions/changes/:id/comments", h.Comment)
+
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("X-User", tt.user)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+		})
+	}
+
+	if stub.personalAllowance.InitAmount != 70000 {
+		t.Errorf("expected approved personal deduction 70000 but got %v", stub.personalAllowance.InitAmount)
+	}
+
+	if stub.kreceiptAllowance.MaxAmount != 50000 {
+		t.Errorf("expected rejected k-receipt deduction to stay 50000 but got %v", stub.kreceiptAllowance.MaxAmount)
+	}
+
+	if len(stub.changes[1].Comments) != 1 || stub.changes[1].Status != ChangeRejected {
+		t.Errorf("expected rejected change with 1 comment but got %v", stub.changes[1])
+	}
+}
+
+func TestDeductionDryRun(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{
+			ID:             1,
+			Type:           "personal",
+			InitAmount:     60000,
+			MinAmount:      10000.0,
+			MaxAmount:      100000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		donationAllowance: &Allowances{
+			ID:             2,
+			Type:           "donation",
+			InitAmount:     0,
+			MinAmount:      0,
+			MaxAmount:      100000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		kreceiptAllowance: &Allowances{
+			ID:             3,
+			Type:           "k-receipt",
+			InitAmount:     0,
+			MinAmount:      0,
+			MaxAmount:      50000.0,
+			LimitMaxAmount: 100000.0,
+			CreatedAt:      "2024-04-22",
+		},
+		samples: []TaxRequest{
+			{TotalIncome: 500000.0},
+			{TotalIncome: 800000.0},
+			{TotalIncome: 570000.0},
+		},
+		err: nil,
+	}
+
+	e := NewEcho()
+	e.POST("/admin/deductions/personal", NewHandler(stub).UpdateInitPersonalDeduct)
+	e.POST("/admin/deductions/k-receipt", NewHandler(stub).UpdateMaxKreceiptDeduct)
+
+	t.Run("Personal deduction 100k on sample population", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/deductions/personal?dryRun=true", strings.NewReader(`{"amount": 100000}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+
+		var got Impact
+		err := json.Unmarshal(rec.Body.Bytes(), &got)
+		if err != nil {
+			t.Errorf("error unmarshalling json: %v", err)
+		}
+
+		want := Impact{
+			Type:           "personal",
+			Current:        60000.0,
+			Proposed:       100000.0,
+			Population:     3,
+			CurrentTax:     136500.0,
+			ProposedTax:    122000.0,
+			TotalChange:    -14500.0,
+			AverageChange:  -14500.0 / 3,
+			Decreased:      3,
+			BracketChanges: 1,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+
+		if stub.personalAllowance.InitAmount != 60000 {
+			t.Errorf("expected personal deduction to stay 60000 but got %v", stub.personalAllowance.InitAmount)
+		}
+	})
+
+	t.Run("K-receipt deduction 100k on uploaded CSV", func(t *testing.T) {
+		body := new(bytes.Buffer)
+		writer := multipart.NewWriter(body)
+		writer.WriteField("amount", "100000")
+		part, err := writer.CreateFormFile("taxFile", "taxes.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("totalIncome,wht,donation,k-receipt\n500000,0,0,100000"))
+		writer.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/deductions/k-receipt?dryRun=true", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status code %d but got %d", http.StatusOK, rec.Code)
+		}
+
+		var got Impact
+		err = json.Unmarshal(rec.Body.Bytes(), &got)
+		if err != nil {
+			t.Errorf("error unmarshalling json: %v", err)
+		}
+
+		if got.Population != 1 || got.TotalChange != -5000.0 || got.Proposed != 100000.0 {
+			t.Errorf("expected 1 taxpayer with change -5000 but got %v", got)
+		}
+
+		if stub.kreceiptAllowance.MaxAmount != 50000 {
+			t.Errorf("expected k-receipt deduction to stay 50000 but got %v", stub.kreceiptAllowance.MaxAmount)
+		}
+	})
+}
+
+func TestDeductorCache(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	dc := NewDeductorCache(stub)
+
+	personal := func() float64 {
+		d, err := dc.Deductor(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return d.initPer("personal")
+	}
+
+	if got := personal(); got != 60000 {
+		t.Errorf("expected personal deduction 60000 but got %v", got)
+	}
+
+	stub.personalAllowance = &Allowances{Type: "personal", InitAmount: 70000}
+	if got := personal(); got != 60000 {
+		t.Errorf("expected cached personal deduction 60000 but got %v", got)
+	}
+
+	dc.Invalidate()
+	if got := personal(); got != 70000 {
+		t.Errorf("expected personal deduction 70000 after invalidate but got %v", got)
+	}
+}
+
+func TestStoreUnavailable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHttp int
+	}{
+		{name: "Database unavailable", err: fmt.Errorf("%w: connection refused", helper.ErrStoreUnavailable), wantHttp: http.StatusServiceUnavailable},
+		{name: "Query failed", err: fmt.Errorf("syntax error"), wantHttp: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEcho()
+			e.POST("/tax/calculations", NewHandler(&Stub{err: tt.err}).Tax)
+
+			req := httptest.NewRequest(http.MethodPost, "/tax/calculations", strings.NewReader(`{"totalIncome": 500000.0, "wht": 0.0, "allowances": []}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+		})
+	}
+}
+
+func TestProblemDetails(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MinAmount: 0, MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MinAmount: 0, MaxAmount: 50000},
+	}
+	e := NewEcho()
+	e.POST("/tax/calculations", NewHandler(stub).Tax)
+
+	tests := []struct {
+		name      string
+		reqBody   string
+		wantCode  string
+		wantField problem.FieldError
+	}{
+		{
+			name:      "Allowance below its minimum",
+			reqBody:   `{"totalIncome": 500000.0, "wht": 0.0, "allowances": [{"allowanceType": "k-receipt", "amount": 100.0}, {"allowanceType": "donation", "amount": -1.0}]}`,
+			wantCode:  CodeInvalidAllowanceAmount,
+			wantField: problem.FieldError{Pointer: "/allowances/1/amount", Rule: "gte", Limit: "0", Message: "Invalid donation amount"},
+		},
+		{
+			name:      "Unknown allowance type",
+			reqBody:   `{"totalIncome": 500000.0, "wht": 0.0, "allowances": [{"allowanceType": "lottery", "amount": 100.0}]}`,
+			wantCode:  problem.CodeValidationFailed,
+			wantField: problem.FieldError{Pointer: "/allowances/0/allowanceType", Rule: "oneof", Limit: "donation k-receipt", Message: "Must be one of donation k-receipt"},
+		},
+		{
+			name:      "WHT more than total income",
+			reqBody:   `{"totalIncome": 500000.0, "wht": 600000.0, "allowances": []}`,
+			wantCode:  CodeInvalidWHT,
+			wantField: problem.FieldError{Pointer: "/wht", Rule: "lte", Limit: "/totalIncome", Message: "Invalid WHT value"},
+		},
+		{
+			name:      "Income of the wrong type",
+			reqBody:   `{"totalIncome": "500000", "wht": 0.0, "allowances": []}`,
+			wantCode:  problem.CodeInvalidBody,
+			wantField: problem.FieldError{Pointer: "/totalIncome", Rule: "type", Limit: "float64", Message: "Must be a float64"},
+		},
+		{
+			name:      "Invalid currency of an income",
+			reqBody:   `{"totalIncome": 0.0, "wht": 0.0, "allowances": [], "incomes": [{"amount": 100.0, "currency": "US", "receivedDate": "2024-01-02"}]}`,
+			wantCode:  CodeInvalidCurrency,
+			wantField: problem.FieldError{Pointer: "/incomes/0/currency", Rule: "iso4217", Message: "Invalid currency code"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/tax/calculations", strings.NewReader(tt.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("Accept-Language", "en")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status code %d but got %d", http.StatusBadRequest, rec.Code)
+			}
+
+			if got := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, problem.MIMEProblemJSON) {
+				t.Errorf("expected content type %s but got %s", problem.MIMEProblemJSON, got)
+			}
+
+			var got problem.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("error unmarshalling json: %v", err)
+			}
+
+			if got.Code != tt.wantCode || got.Status != http.StatusBadRequest || got.Instance != "/tax/calculations" {
+				t.Errorf("expected code %s status 400 instance /tax/calculations but got %+v", tt.wantCode, got)
+			}
+
+			if !reflect.DeepEqual(got.Errors, []problem.FieldError{tt.wantField}) {
+				t.Errorf("expected field errors [%+v] but got %+v", tt.wantField, got.Errors)
+			}
+		})
+	}
+}
+
+func TestTaxLevelLanguage(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	e := NewEcho()
+	e.Use(i18n.Middleware)
+	e.POST("/tax/calculations", NewHandler(stub).Tax)
+
+	tests := []struct {
+		name     string
+		url      string
+		accept   string
+		wantLang string
+		want     []string
+	}{
+		{
+			name:     "Thai by default",
+			url:      "/tax/calculations",
+			wantLang: i18n.Thai,
+			want:     []string{"0 - 150,000", "150,001 - 500,000", "500,001 - 1,000,000", "1,000,001 - 2,000,000", "2,000,000 ขึ้นไป"},
+		},
+		{
+			name:     "English from Accept-Language",
+			url:      "/tax/calculations",
+			accept:   "en-GB,en;q=0.9",
+			wantLang: i18n.English,
+			want:     []string{"0 - 150,000", "150,001 - 500,000", "500,001 - 1,000,000", "1,000,001 - 2,000,000", "2,000,000 and above"},
+		},
+		{
+			name:     "English from lang parameter",
+			url:      "/tax/calculations?lang=en",
+			accept:   "th",
+			wantLang: i18n.English,
+			want:     []string{"0 - 150,000", "150,001 - 500,000", "500,001 - 1,000,000", "1,000,001 - 2,000,000", "2,000,000 and above"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.url, strings.NewReader(`{"totalIncome": 500000.0, "wht": 0.0, "allowances": []}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(i18n.HeaderContentLanguage); got != tt.wantLang {
+				t.Errorf("expected content language %s but got %s", tt.wantLang, got)
+			}
+
+			var got TaxResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("error unmarshalling json: %v", err)
+			}
+
+			var levels []string
+			for _, l := range got.TaxLevels {
+				levels = append(levels, l.Level)
+			}
+			if !reflect.DeepEqual(levels, tt.want) {
+				t.Errorf("expected %v but got %v", tt.want, levels)
+			}
+		})
+	}
+}
+
+func TestCalculationMetrics(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	e := NewEcho()
+	e.POST("/tax/calculations", NewHandler(stub).Tax)
+
+	tests := []struct {
+		name    string
+		reqBody string
+		bracket string
+		result  string
+	}{
+		{name: "Payment in the 10% bracket", reqBody: `{"totalIncome": 500000.0, "wht": 0.0, "allowances": []}`, bracket: "10%", result: metrics.ResultPayment},
+		{name: "Refund in the 10% bracket", reqBody: `{"totalIncome": 500000.0, "wht": 30000.0, "allowances": []}`, bracket: "10%", result: metrics.ResultRefund},
+		{name: "Nothing to pay in the 0% bracket", reqBody: `{"totalIncome": 150000.0, "wht": 0.0, "allowances": []}`, bracket: "0%", result: metrics.ResultNone},
+	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			calculations := testutil.ToFloat64(metrics.Calculations.WithLabelValues(tt.bracket))
+			results := testutil.ToFloat64(metrics.CalculationResults.WithLabelValues(tt.result))
 
-			reqBodyStr, err := json.Marshal(tt.reqBody)
-			if err != nil {
-				t.Errorf("error marshalling json: %v", err)
-			}
-
-			req := httptest.NewRequest(http.MethodPost, "/admin/deductions/personal", strings.NewReader(string(reqBodyStr)))
-			req.SetBasicAuth(tt.username, tt.password)
+			req := httptest.NewRequest(http.MethodPost, "/tax/calculations", strings.NewReader(tt.reqBody))
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
-
 			rec := httptest.NewRecorder()
-			c := e.NewContext(req, rec)
-			c.SetPath("/admin/deductions/personal")
 			e.ServeHTTP(rec, req)
 
-			if rec.Code != tt.httpWant {
-				t.Errorf("expected status code %d but got %d", tt.httpWant, rec.Code)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status code %d but got %d", http.StatusOK, rec.Code)
 			}
 
-			var got InitPersonalDeductRes
-			err = json.Unmarshal(rec.Body.Bytes(), &got)
-			if err != nil {
-				t.Errorf("error unmarshalling json: %v", err)
+			if got := testutil.ToFloat64(metrics.Calculations.WithLabelValues(tt.bracket)) - calculations; got != 1 {
+				t.Errorf("expected 1 calculation in the %s bracket but got %v", tt.bracket, got)
 			}
 
-			if !reflect.DeepEqual(got, tt.wantRes) {
-				t.Errorf("expected %v but got %v", tt.wantRes, got)
+			if got := testutil.ToFloat64(metrics.CalculationResults.WithLabelValues(tt.result)) - results; got != 1 {
+				t.Errorf("expected 1 %s result but got %v", tt.result, got)
 			}
 		})
 	}
 }
 
+func TestCalculatorSkipsMetrics(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	calculations := testutil.ToFloat64(metrics.Calculations.WithLabelValues("10%"))
+
+	calculator := NewCalculator(stub)
+	_, err := calculator.Tax(context.Background(), TaxRequestV2{TaxRequest: TaxRequest{TotalIncome: 500000.0}, TaxYear: 2024}, i18n.NewPrinter(i18n.English))
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	_, err = calculator.Batch(context.Background(), 2024, []TaxRequest{{TotalIncome: 500000.0}})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.Calculations.WithLabelValues("10%")) - calculations; got != 0 {
+		t.Errorf("expected no calculations to be observed but got %v", got)
+	}
+}
+
+func TestTaxRequestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger.New(&buf, slog.LevelInfo).Info("calculating", "request", TaxRequest{
+		TotalIncome: 512345.0,
+		WHT:         24680.0,
+		Incomes:     []IncomeReq{{Amount: 13579.0, Currency: "USD", ReceivedDate: "2024-03-01"}},
+		Allowances:  []AllowanceReq{{AllowanceType: "donation", Amount: 97531.0}},
+	})
+
+	for _, amount := range []string{"512345", "24680", "13579", "97531"} {
+		if strings.Contains(buf.String(), amount) {
+			t.Errorf("expected %s to be kept out of the log but got %s", amount, buf.String())
+		}
+	}
+}
+
 func TestUploadCsv(t *testing.T) {
 
 	//for compare nil value
@@ -522,6 +1609,20 @@ func TestUploadCsv(t *testing.T) {
 			wantHttp: http.StatusBadRequest,
 			wantRes:  TaxUploadResponse{},
 		},
+		{
+			name:     "K-receipt column isn't part of the upload format",
+			fileName: "example.csv",
+			content:  "totalIncome,wht,donation,k-receipt\n500000,0,0,50000",
+			wantHttp: http.StatusBadRequest,
+			wantRes:  TaxUploadResponse{},
+		},
+		{
+			name:     "Header with too few columns",
+			fileName: "example.csv",
+			content:  "totalIncome\n500000",
+			wantHttp: http.StatusBadRequest,
+			wantRes:  TaxUploadResponse{},
+		},
 	}
 
 	stub := &Stub{
@@ -628,6 +1729,86 @@ func TestUploadCsv(t *testing.T) {
 	}
 }
 
+func TestUploadExchangeRateCsv(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		wantHttp int
+		wantRes  ExchangeRateUploadResponse
+	}{
+		{
+			name:     "Input correct csv format",
+			fileName: "rates.csv",
+			content:  "date,currency,rate\n2024-01-02,usd,34.5\n2024-01-02,JPY,0.24",
+			wantHttp: http.StatusOK,
+			wantRes: ExchangeRateUploadResponse{
+				Rates: []ExchangeRate{
+					{Date: "2024-01-02", Currency: "USD", Rate: 34.5},
+					{Date: "2024-01-02", Currency: "JPY", Rate: 0.24},
+				},
+			},
+		},
+		{
+			name:     "Input incorrect csv header",
+			fileName: "rates.csv",
+			content:  "totalIncome,wht,donation\n500000,0,0",
+			wantHttp: http.StatusBadRequest,
+		},
+		{
+			name:     "Rate must be more than 0",
+			fileName: "rates.csv",
+			content:  "date,currency,rate\n2024-01-02,USD,-1",
+			wantHttp: http.StatusBadRequest,
+		},
+	}
+
+	e := NewEcho()
+	e.POST("/admin/exchange-rates/upload-csv", NewHandler(&Stub{}).UploadExchangeRateCsv)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := new(bytes.Buffer)
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("rateFile", tt.fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := part.Write([]byte(tt.content)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := writer.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/exchange-rates/upload-csv", body)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var got ExchangeRateUploadResponse
+			err = json.Unmarshal(rec.Body.Bytes(), &got)
+			if err != nil {
+				t.Errorf("error unmarshalling json: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("expected %v but got %v", tt.wantRes, got)
+			}
+		})
+	}
+}
+
 func TestSetKreceiptDeduction(t *testing.T) {
 
 	tests := []struct {
@@ -741,3 +1922,378 @@ func TestSetKreceiptDeduction(t *testing.T) {
 		})
 	}
 }
+
+// levelsV2 are the tax levels of netIncome the way v2 answers them.
+func levelsV2(netIncome float64) []TaxLevelV2 {
+	var levels []TaxLevelV2
+	for _, l := range taxLevel(netIncome) {
+		levels = append(levels, TaxLevelV2{Level: l.Level, Tax: decimal(l.Tax)})
+	}
+	return levels
+}
+
+func TestTaxV2(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	e := NewEcho()
+	e.POST("/v2/tax/calculations", NewHandler(stub).TaxV2)
+
+	defer func(clock func() time.Time) { now = clock }(now)
+	now = func() time.Time { return time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		reqBody  string
+		wantHttp int
+		wantRes  TaxResponseV2
+		wantCode string
+	}{
+		{
+			name:     "Refund is zero when tax is payable",
+			reqBody:  `{"taxYear": 2025, "totalIncome": 500000.0, "wht": 0.0, "allowances": [{"allowanceType": "donation", "amount": 0.0}]}`,
+			wantHttp: http.StatusOK,
+			wantRes: TaxResponseV2{
+				TaxYear: 2025, Tax: "29000.00", TaxRefund: "0.00",
+				Summary: TaxSummary{
+					TotalIncome: "500000.00", Allowances: "60000.00", NetIncome: "440000.00",
+					LevelTax: "29000.00", TaxPaid: "0.00", Credits: "0.00",
+				},
+				TaxLevels:   levelsV2(440000),
+				Credits:     []CreditResV2{},
+				Conversions: []ConversionV2{},
+			},
+		},
+		{
+			name:     "Tax is zero when WHT is refunded",
+			reqBody:  `{"taxYear": 2024, "totalIncome": 500000.0, "wht": 40000.0, "allowances": [{"allowanceType": "donation", "amount": 200000.0}]}`,
+			wantHttp: http.StatusOK,
+			wantRes: TaxResponseV2{
+				TaxYear: 2024, Tax: "0.00", TaxRefund: "21000.00",
+				Summary: TaxSummary{
+					TotalIncome: "500000.00", Allowances: "160000.00", NetIncome: "340000.00",
+					LevelTax: "19000.00", TaxPaid: "40000.00", Credits: "0.00",
+				},
+				TaxLevels:   levelsV2(340000),
+				Credits:     []CreditResV2{},
+				Conversions: []ConversionV2{},
+			},
+		},
+		{
+			name:     "Tax year defaults to last year",
+			reqBody:  `{"totalIncome": 100000.0, "wht": 0.0, "allowances": []}`,
+			wantHttp: http.StatusOK,
+			wantRes: TaxResponseV2{
+				TaxYear: 2025, Tax: "0.00", TaxRefund: "0.00",
+				Summary: TaxSummary{
+					TotalIncome: "100000.00", Allowances: "60000.00", NetIncome: "40000.00",
+					LevelTax: "0.00", TaxPaid: "0.00", Credits: "0.00",
+				},
+				TaxLevels:   levelsV2(40000),
+				Credits:     []CreditResV2{},
+				Conversions: []ConversionV2{},
+			},
+		},
+		{
+			name:     "Tax year before the tax levels",
+			reqBody:  `{"taxYear": 2016, "totalIncome": 500000.0, "wht": 0.0, "allowances": []}`,
+			wantHttp: http.StatusBadRequest,
+			wantCode: CodeInvalidTaxYear,
+		},
+		{
+			name:     "Tax year in the future",
+			reqBody:  `{"taxYear": 2027, "totalIncome": 500000.0, "wht": 0.0, "allowances": []}`,
+			wantHttp: http.StatusBadRequest,
+			wantCode: CodeInvalidTaxYear,
+		},
+		{
+			name:     "Request errors are shared with v1",
+			reqBody:  `{"taxYear": 2025, "totalIncome": 500000.0, "wht": 600000.0, "allowances": []}`,
+			wantHttp: http.StatusBadRequest,
+			wantCode: CodeInvalidWHT,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v2/tax/calculations", strings.NewReader(tt.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantHttp {
+				t.Errorf("expected status code %d but got %d", tt.wantHttp, rec.Code)
+			}
+
+			if tt.wantCode != "" {
+				var got problem.Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+					t.Fatalf("error unmarshalling json: %v", err)
+				}
+				if got.Code != tt.wantCode {
+					t.Errorf("expected code %s but got %s", tt.wantCode, got.Code)
+				}
+				return
+			}
+
+			var got TaxResponseV2
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("error unmarshalling json: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.wantRes) {
+				t.Errorf("expected %+v but got %+v", tt.wantRes, got)
+			}
+		})
+	}
+}
+
+func TestUploadCsvV2(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	e := NewEcho()
+	e.POST("/v2/tax/calculations/upload-csv", NewHandler(stub).UploadCsvV2)
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("taxYear", "2024"); err != nil {
+		t.Fatal(err)
+	}
+	part, err := writer.CreateFormFile("taxFile", "taxes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(part, "totalIncome,wht,donation\n500000,0,0\n600000,40000,20000\n")
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/tax/calculations/upload-csv", body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status code %d but got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var got TaxUploadResponseV2
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("error unmarshalling json: %v", err)
+	}
+	want := TaxUploadResponseV2{
+		TaxYear: 2024,
+		Taxes: []TaxUploadV2{
+			{TotalIncome: "500000.00", Tax: "29000.00", TaxRefund: "0.00"},
+			{TotalIncome: "600000.00", Tax: "0.00", TaxRefund: "2000.00"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v but got %+v", want, got)
+	}
+}
+
+// dialGRPC serves s over an in-memory connection and returns a client of it.
+func dialGRPC(t *testing.T, s *GRPCServer) taxpb.TaxServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	taxpb.RegisterTaxServiceServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return taxpb.NewTaxServiceClient(conn)
+}
+
+// grpcReason returns the problem code in the details of a gRPC error.
+func grpcReason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestGRPC(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000, MinAmount: 10000, MaxAmount: 100000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000, LimitMaxAmount: 100000},
+	}
+	client := dialGRPC(t, NewGRPCServer(NewHandler(stub), nil))
+
+	defer func(clock func() time.Time) { now = clock }(now)
+	now = func() time.Time { return time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	t.Run("CalculateTax", func(t *testing.T) {
+		res, err := client.CalculateTax(ctx, &taxpb.TaxRequest{TotalIncome: 500000, Wht: 40000})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if res.TaxYear != 2025 || res.Tax != 0 || res.TaxRefund != 11000 {
+			t.Errorf("expected tax year 2025, tax 0 and refund 11000 but got %d, %v and %v", res.TaxYear, res.Tax, res.TaxRefund)
+		}
+		if res.Summary.NetIncome != 440000 || res.Summary.TaxPaid != 40000 {
+			t.Errorf("expected net income 440000 and tax paid 40000 but got %v and %v", res.Summary.NetIncome, res.Summary.TaxPaid)
+		}
+		if len(res.TaxLevels) != len(steps) {
+			t.Errorf("expected %d tax levels but got %d", len(steps), len(res.TaxLevels))
+		}
+	})
+
+	t.Run("CalculateTax with an invalid request", func(t *testing.T) {
+		_, err := client.CalculateTax(ctx, &taxpb.TaxRequest{TotalIncome: 500000, Wht: 600000})
+		if got := status.Code(err); got != codes.InvalidArgument {
+			t.Errorf("expected code %s but got %s", codes.InvalidArgument, got)
+		}
+		if got := grpcReason(err); got != CodeInvalidWHT {
+			t.Errorf("expected reason %s but got %s", CodeInvalidWHT, got)
+		}
+	})
+
+	t.Run("CalculateBatch", func(t *testing.T) {
+		stream, err := client.CalculateBatch(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		requests := []*taxpb.TaxRequest{
+			{TotalIncome: 500000},
+			{TotalIncome: 500000, Wht: -1},
+			{TotalIncome: 1000000, Allowances: []*taxpb.AllowanceRequest{{AllowanceType: "donation", Amount: 200000}}},
+		}
+		for _, req := range requests {
+			if err := stream.Send(req); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := stream.CloseSend(); err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch r := res.Result.(type) {
+			case *taxpb.BatchResult_Response:
+				got = append(got, fmt.Sprintf("%d:%v", res.Index, r.Response.Tax))
+			case *taxpb.BatchResult_Problem:
+				got = append(got, fmt.Sprintf("%d:%s", res.Index, r.Problem.Code))
+			}
+		}
+
+		want := []string{"0:29000", "1:" + CodeInvalidWHT, "2:86000"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v but got %v", want, got)
+		}
+	})
+
+	t.Run("GetDeductions", func(t *testing.T) {
+		res, err := client.GetDeductions(ctx, &taxpb.GetDeductionsRequest{})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		var got []string
+		for _, d := range res.Deductions {
+			got = append(got, d.Type)
+		}
+		if !reflect.DeepEqual(got, allowanceTypes) {
+			t.Errorf("expected %v but got %v", allowanceTypes, got)
+		}
+	})
+
+	t.Run("UpdateDeduction", func(t *testing.T) {
+		res, err := client.UpdateDeduction(ctx, &taxpb.UpdateDeductionRequest{Type: "personal", Amount: 70000})
+		if err != nil {
+			t.Fatalf("expected no error but got %v", err)
+		}
+		if got := res.GetDeduction().GetInitAmount(); got != 70000 {
+			t.Errorf("expected personal deduction 70000 but got %v", got)
+		}
+
+		_, err = client.UpdateDeduction(ctx, &taxpb.UpdateDeductionRequest{Type: "donation", Amount: 70000})
+		if got := grpcReason(err); got != CodeInvalidDeductionType {
+			t.Errorf("expected reason %s but got %s", CodeInvalidDeductionType, got)
+		}
+	})
+}
+
+func TestGRPCDeductionApproval(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000, MinAmount: 10000, MaxAmount: 100000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000, LimitMaxAmount: 100000},
+	}
+	h := NewHandler(stub)
+	client := dialGRPC(t, NewGRPCServer(h, NewApprovalHandler(stub, stub, h.Deductors())))
+
+	res, err := client.UpdateDeduction(context.Background(), &taxpb.UpdateDeductionRequest{Type: "k-receipt", Amount: 80000})
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+	if got := res.GetChange().GetStatus(); got != ChangePending {
+		t.Errorf("expected a %s change but got %q", ChangePending, got)
+	}
+	if stub.kreceiptAllowance.MaxAmount != 50000 {
+		t.Errorf("expected k-receipt to stay 50000 until approved but got %v", stub.kreceiptAllowance.MaxAmount)
+	}
+}
+
+func TestCalculatorExplain(t *testing.T) {
+	stub := &Stub{
+		personalAllowance: &Allowances{Type: "personal", InitAmount: 60000},
+		donationAllowance: &Allowances{Type: "donation", MaxAmount: 100000},
+		kreceiptAllowance: &Allowances{Type: "k-receipt", MaxAmount: 50000},
+	}
+	req := TaxRequestV2{TaxRequest: TaxRequest{
+		TotalIncome: 700000,
+		Allowances:  []AllowanceReq{{AllowanceType: "donation", Amount: 200000}, {AllowanceType: "k-receipt", Amount: 20000}},
+	}, TaxYear: 2024}
+
+	e, err := NewCalculator(stub).Explain(context.Background(), req, i18n.NewPrinter(i18n.English))
+	if err != nil {
+		t.Fatalf("expected no error but got %v", err)
+	}
+
+	wantAllowances := []AllowanceUse{
+		{Type: "personal", Claimed: 60000, Deducted: 60000},
+		{Type: "donation", Claimed: 200000, Deducted: 100000},
+		{Type: "k-receipt", Claimed: 20000, Deducted: 20000},
+	}
+	if !reflect.DeepEqual(e.Allowances, wantAllowances) {
+		t.Errorf("expected allowances %v but got %v", wantAllowances, e.Allowances)
+	}
+
+	wantLevels := []LevelUse{
+		{Level: "0 - 150,000", Rate: 0, Income: 150000, Tax: 0},
+		{Level: "150,001 - 500,000", Rate: 0.1, Income: 350000, Tax: 35000},
+		{Level: "500,001 - 1,000,000", Rate: 0.15, Income: 20000, Tax: 3000},
+		{Level: "1,000,001 - 2,000,000", Rate: 0.2, Income: 0, Tax: 0},
+		{Level: "2,000,000 and above", Rate: 0.35, Income: 0, Tax: 0},
+	}
+	if !reflect.DeepEqual(e.Levels, wantLevels) {
+		t.Errorf("expected levels %v but got %v", wantLevels, e.Levels)
+	}
+
+	if e.Response.Tax != "38000.00" || e.Response.Summary.NetIncome != "520000.00" {
+		t.Errorf("expected tax 38000.00 on 520000.00 but got %s on %s", e.Response.Tax, e.Response.Summary.NetIncome)
+	}
+}
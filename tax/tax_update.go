@@ -1,32 +1,38 @@
 package tax
 
-import (
-	"fmt"
-)
+import "context"
 
-func validateInitPersonalDeduction(s Storer, amount float64) (int, error) {
+func validateInitPersonalDeduction(ctx context.Context, s Storer, amount float64) (int, error) {
 
-	p, err := s.PersonalAllowance()
+	p, err := s.PersonalAllowance(ctx)
 	if err != nil {
-		return 500, fmt.Errorf("Internal Server Error")
+		return storeStatus(ctx, err)
 	}
 
-	if amount < p.MinAmount || amount > p.MaxAmount {
-		return 400, fmt.Errorf("Invalid personal deduction amount")
+	if amount < p.MinAmount {
+		return 400, invalid(CodeInvalidDeduction, "/amount", "gte", limit(p.MinAmount), "Invalid personal deduction amount")
+	}
+
+	if amount > p.MaxAmount {
+		return 400, invalid(CodeInvalidDeduction, "/amount", "lte", limit(p.MaxAmount), "Invalid personal deduction amount")
 	}
 
 	return 200, nil
 }
 
-func validateMaxKreceipt(s Storer, amount float64) (int, error) {
+func validateMaxKreceipt(ctx context.Context, s Storer, amount float64) (int, error) {
 
-	k, err := s.KreceiptAllowance()
+	k, err := s.KreceiptAllowance(ctx)
 	if err != nil {
-		return 500, fmt.Errorf("Internal Server Error")
+		return storeStatus(ctx, err)
+	}
+
+	if amount < k.MinAmount {
+		return 400, invalid(CodeInvalidDeduction, "/amount", "gte", limit(k.MinAmount), "Invalid K-receipt deduction amount")
 	}
 
-	if amount < k.MinAmount || amount > k.LimitMaxAmount {
-		return 400, fmt.Errorf("Invalid K-receipt deduction amount")
+	if amount > k.LimitMaxAmount {
+		return 400, invalid(CodeInvalidDeduction, "/amount", "lte", limit(k.LimitMaxAmount), "Invalid K-receipt deduction amount")
 	}
 
 	return 200, nil
@@ -3,8 +3,12 @@ package tax
 import (
 	"encoding/csv"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"slices"
 	"strconv"
+
+	"github.com/Gitong23/assessment-tax/problem"
 )
 
 func OpenFormFile(files []*multipart.FileHeader) ([]multipart.File, error) {
@@ -20,31 +24,42 @@ func OpenFormFile(files []*multipart.FileHeader) ([]multipart.File, error) {
 	return src, nil
 }
 
+var (
+	// taxHeader is the header of the CSV files taxes are calculated from.
+	taxHeader = []string{"totalIncome", "wht", "donation"}
+
+	// sampleHeader is the header of the sample population, which may also
+	// claim a k-receipt deduction so its changes can be measured.
+	sampleHeader = []string{"totalIncome", "wht", "donation", "k-receipt"}
+)
+
 func isCorrectHeader(record []string) bool {
-	if record[0] != "totalIncome" || record[1] != "wht" || record[2] != "donation" {
-		return false
-	}
-	return true
+	return slices.Equal(record, taxHeader)
+}
+
+// isSampleHeader accepts an optional k-receipt column after donation.
+func isSampleHeader(record []string) bool {
+	return slices.Equal(record, taxHeader) || slices.Equal(record, sampleHeader)
 }
 
 func csvTaxReq(record []string) (*TaxRequest, error) {
 	if len(record) != 3 {
-		return nil, fmt.Errorf("Invalid CSV file content")
+		return nil, problem.Invalid(CodeInvalidCSV, "Invalid CSV file content")
 	}
 
 	income, err := strconv.ParseFloat(record[0], 64)
 	if err != nil {
-		return nil, fmt.Errorf("Invalid TotalIncome value")
+		return nil, invalid(CodeInvalidNumber, "/totalIncome", "number", "", "Invalid TotalIncome value")
 	}
 
 	wht, err := strconv.ParseFloat(record[1], 64)
 	if err != nil {
-		return nil, fmt.Errorf("Invalid WHT value")
+		return nil, invalid(CodeInvalidNumber, "/wht", "number", "", "Invalid WHT value")
 	}
 
 	donationAmount, err := strconv.ParseFloat(record[2], 64)
 	if err != nil {
-		return nil, fmt.Errorf("Invalid Donation value")
+		return nil, invalid(CodeInvalidNumber, "/donation", "number", "", "Invalid Donation value")
 	}
 
 	return &TaxRequest{
@@ -59,19 +74,48 @@ func csvTaxReq(record []string) (*TaxRequest, error) {
 	}, nil
 }
 
+func csvSampleReq(record []string) (*TaxRequest, error) {
+	if len(record) != 3 && len(record) != 4 {
+		return nil, problem.Invalid(CodeInvalidCSV, "Invalid CSV file content")
+	}
+
+	taxReq, err := csvTaxReq(record[:3])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(record) == 4 {
+		kReceiptAmount, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, invalid(CodeInvalidNumber, "/k-receipt", "number", "", "Invalid K-receipt value")
+		}
+
+		taxReq.Allowances = append(taxReq.Allowances, AllowanceReq{
+			AllowanceType: "k-receipt",
+			Amount:        kReceiptAmount,
+		})
+	}
+	return taxReq, nil
+}
+
 func appendTaxReq(t *[]TaxRequest, rec [][]string) error {
+	return appendRows(t, rec, isCorrectHeader, csvTaxReq)
+}
 
+// appendRows appends the requests parsed from the rows of rec after its
+// header.
+func appendRows(t *[]TaxRequest, rec [][]string, header func([]string) bool, parse func([]string) (*TaxRequest, error)) error {
 	for idx, r := range rec {
 		if idx == 0 {
-			if !isCorrectHeader(r) {
-				return fmt.Errorf("Invalid CSV header")
+			if !header(r) {
+				return problem.Invalid(CodeInvalidCSVHeader, "Invalid CSV header")
 			}
 			continue
 		}
 
-		taxReq, err := csvTaxReq(r)
+		taxReq, err := parse(r)
 		if err != nil {
-			return err
+			return problem.Nest(err, fmt.Sprintf("/rows/%d", len(*t)))
 		}
 		*t = append(*t, *taxReq)
 	}
@@ -79,16 +123,25 @@ func appendTaxReq(t *[]TaxRequest, rec [][]string) error {
 	return nil
 }
 
-func readFileCsv(f multipart.File) (records [][]string, err error) {
+func readFileCsv(f io.Reader) (records [][]string, err error) {
 	reader := csv.NewReader(f)
 	records, err = reader.ReadAll()
 	if err != nil {
-		return nil, fmt.Errorf("Invalid CSV file")
+		return nil, problem.Invalid(CodeInvalidCSV, "Invalid CSV file")
 	}
 	return records, nil
 }
 
 func fileTaxReq(src []multipart.File) ([]TaxRequest, error) {
+	return fileRows(src, isCorrectHeader, csvTaxReq)
+}
+
+// fileSampleReq reads uploaded files in the format of the sample population.
+func fileSampleReq(src []multipart.File) ([]TaxRequest, error) {
+	return fileRows(src, isSampleHeader, csvSampleReq)
+}
+
+func fileRows(src []multipart.File, header func([]string) bool, parse func([]string) (*TaxRequest, error)) ([]TaxRequest, error) {
 	var taxsReq []TaxRequest
 	for _, s := range src {
 		records, err := readFileCsv(s)
@@ -96,7 +149,7 @@ func fileTaxReq(src []multipart.File) ([]TaxRequest, error) {
 			return nil, err
 		}
 
-		err = appendTaxReq(&taxsReq, records)
+		err = appendRows(&taxsReq, records, header, parse)
 		if err != nil {
 			return nil, err
 		}
@@ -108,9 +161,7 @@ func NewTaxUploadResponse(t []TaxRequest, d *Deductor) *TaxUploadResponse {
 
 	var ts []TaxUpload
 	for _, tr := range t {
-		i := tr.TotalIncome - d.total(tr.Allowances)
-		taxUp := NewTaxUpload(tr, i)
-		ts = append(ts, taxUp)
+		ts = append(ts, NewTaxUpload(tr, d.netIncome(tr)))
 	}
 
 	return &TaxUploadResponse{Taxs: ts}
@@ -0,0 +1,307 @@
+package tax
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Gitong23/assessment-tax/problem"
+	"github.com/Gitong23/assessment-tax/tracing"
+	"github.com/labstack/echo/v4"
+)
+
+// minTaxYear is the first tax year the levels in steps apply to. They are
+// the only levels, so the tax year of a request labels its result without
+// changing it.
+const minTaxYear = 2017
+
+// now is the clock the default tax year is read from.
+var now = time.Now
+
+// summary is how the tax of a TaxResponse was reached.
+type summary struct {
+	income    float64
+	netIncome float64
+	levelTax  float64
+	taxPaid   float64
+	credits   float64
+}
+
+// The v2 contract answers every amount as a decimal string with two
+// places, and every field whether or not it has a value.
+type (
+	TaxRequestV2 struct {
+		TaxRequest
+		TaxYear int `json:"taxYear,omitempty" doc:"The tax year filed for, from 2017, which defaults to last year. It labels the result without changing the tax levels."`
+	}
+
+	HouseholdRequestV2 struct {
+		HouseholdRequest
+		TaxYear int `json:"taxYear,omitempty" doc:"The tax year the spouses file for, which defaults to last year."`
+	}
+
+	TaxSummary struct {
+		TotalIncome string `json:"totalIncome" format:"decimal"`
+		Allowances  string `json:"allowances" format:"decimal"`
+		NetIncome   string `json:"netIncome" format:"decimal"`
+		LevelTax    string `json:"levelTax" format:"decimal"`
+		TaxPaid     string `json:"taxPaid" format:"decimal"`
+		Credits     string `json:"credits" format:"decimal"`
+	}
+
+	TaxLevelV2 struct {
+		Level string `json:"level"`
+		Tax   string `json:"tax" format:"decimal"`
+	}
+
+	CreditResV2 struct {
+		CreditType string `json:"creditType"`
+		Amount     string `json:"amount" format:"decimal"`
+		Applied    string `json:"applied" format:"decimal"`
+		Unused     string `json:"unused" format:"decimal"`
+	}
+
+	SurchargeMonthV2 struct {
+		Month  int    `json:"month"`
+		Amount string `json:"amount" format:"decimal"`
+	}
+
+	SurchargeV2 struct {
+		Months    int                `json:"months"`
+		Amount    string             `json:"amount" format:"decimal"`
+		Penalty   string             `json:"penalty" format:"decimal"`
+		Total     string             `json:"total" format:"decimal"`
+		Breakdown []SurchargeMonthV2 `json:"breakdown"`
+	}
+
+	ConversionV2 struct {
+		Currency     string `json:"currency"`
+		ReceivedDate string `json:"receivedDate"`
+		RateDate     string `json:"rateDate"`
+		Rate         string `json:"rate" format:"decimal"`
+		Amount       string `json:"amount" format:"decimal"`
+		WHT          string `json:"wht" format:"decimal"`
+		AmountTHB    string `json:"amountThb" format:"decimal"`
+		WHTTHB       string `json:"whtThb" format:"decimal"`
+	}
+
+	TaxResponseV2 struct {
+		TaxYear     int            `json:"taxYear"`
+		Tax         string         `json:"tax" format:"decimal"`
+		TaxRefund   string         `json:"taxRefund" format:"decimal"`
+		Summary     TaxSummary     `json:"summary"`
+		TaxLevels   []TaxLevelV2   `json:"taxLevels"`
+		Credits     []CreditResV2  `json:"credits"`
+		Surcharge   *SurchargeV2   `json:"surcharge"`
+		Conversions []ConversionV2 `json:"conversions"`
+	}
+
+	HouseholdOptionV2 struct {
+		Filing    string          `json:"filing"`
+		Tax       string          `json:"tax" format:"decimal"`
+		TaxRefund string          `json:"taxRefund" format:"decimal"`
+		Results   []TaxResponseV2 `json:"results"`
+	}
+
+	HouseholdResponseV2 struct {
+		TaxYear     int                 `json:"taxYear"`
+		Options     []HouseholdOptionV2 `json:"options"`
+		Recommended string              `json:"recommended"`
+	}
+
+	TaxUploadV2 struct {
+		TotalIncome string `json:"totalIncome" format:"decimal"`
+		Tax         string `json:"tax" format:"decimal"`
+		TaxRefund   string `json:"taxRefund" format:"decimal"`
+	}
+
+	TaxUploadResponseV2 struct {
+		TaxYear int           `json:"taxYear"`
+		Taxes   []TaxUploadV2 `json:"taxes"`
+	}
+)
+
+// decimal formats an amount of baht.
+func decimal(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// taxYear returns year, or the year before the current one, which annual
+// returns are filed for, when it's 0.
+func taxYear(year int) (int, error) {
+	current := now().Year()
+	switch {
+	case year == 0:
+		return current - 1, nil
+	case year < minTaxYear:
+		return 0, invalid(CodeInvalidTaxYear, "/taxYear", "gte", strconv.Itoa(minTaxYear), "Invalid tax year")
+	case year > current:
+		return 0, invalid(CodeInvalidTaxYear, "/taxYear", "lte", strconv.Itoa(current), "Invalid tax year")
+	}
+	return year, nil
+}
+
+func refund(r TaxResponse) float64 {
+	refund, _ := r.TaxRefund.(float64)
+	return refund
+}
+
+func newTaxResponseV2(year int, r TaxResponse) TaxResponseV2 {
+	res := TaxResponseV2{
+		TaxYear:   year,
+		Tax:       decimal(r.Tax),
+		TaxRefund: decimal(refund(r)),
+		Summary: TaxSummary{
+			TotalIncome: decimal(r.summary.income),
+			Allowances:  decimal(r.summary.income - r.summary.netIncome),
+			NetIncome:   decimal(max(r.summary.netIncome, 0)),
+			LevelTax:    decimal(r.summary.levelTax),
+			TaxPaid:     decimal(r.summary.taxPaid),
+			Credits:     decimal(r.summary.credits),
+		},
+		TaxLevels:   []TaxLevelV2{},
+		Credits:     []CreditResV2{},
+		Conversions: []ConversionV2{},
+	}
+
+	for _, l := range r.TaxLevels {
+		res.TaxLevels = append(res.TaxLevels, TaxLevelV2{Level: l.Level, Tax: decimal(l.Tax)})
+	}
+
+	for _, c := range r.Credits {
+		res.Credits = append(res.Credits, CreditResV2{
+			CreditType: c.CreditType,
+			Amount:     decimal(c.Amount),
+			Applied:    decimal(c.Applied),
+			Unused:     decimal(c.Unused),
+		})
+	}
+
+	if s := r.Surcharge; s != nil {
+		res.Surcharge = &SurchargeV2{
+			Months:    s.Months,
+			Amount:    decimal(s.Amount),
+			Penalty:   decimal(s.Penalty),
+			Total:     decimal(s.Total),
+			Breakdown: []SurchargeMonthV2{},
+		}
+		for _, m := range s.Breakdown {
+			res.Surcharge.Breakdown = append(res.Surcharge.Breakdown, SurchargeMonthV2{Month: m.Month, Amount: decimal(m.Amount)})
+		}
+	}
+
+	for _, c := range r.Conversions {
+		res.Conversions = append(res.Conversions, ConversionV2{
+			Currency:     c.Currency,
+			ReceivedDate: c.ReceivedDate,
+			RateDate:     c.RateDate,
+			Rate:         limit(c.Rate),
+			Amount:       decimal(c.Amount),
+			WHT:          decimal(c.WHT),
+			AmountTHB:    decimal(c.AmountTHB),
+			WHTTHB:       decimal(c.WHTTHB),
+		})
+	}
+	return res
+}
+
+func newHouseholdResponseV2(year int, r *HouseholdResponse) HouseholdResponseV2 {
+	res := HouseholdResponseV2{TaxYear: year, Options: []HouseholdOptionV2{}, Recommended: r.Recommended}
+	for _, o := range r.Options {
+		option := HouseholdOptionV2{
+			Filing:    o.Filing,
+			Tax:       decimal(o.Tax),
+			TaxRefund: decimal(o.TaxRefund),
+			Results:   []TaxResponseV2{},
+		}
+		for _, result := range o.Results {
+			option.Results = append(option.Results, newTaxResponseV2(year, result))
+		}
+		res.Options = append(res.Options, option)
+	}
+	return res
+}
+
+func newTaxUploadResponseV2(year int, r *TaxUploadResponse) TaxUploadResponseV2 {
+	res := TaxUploadResponseV2{TaxYear: year, Taxes: []TaxUploadV2{}}
+	for _, t := range r.Taxs {
+		upload := TaxUploadV2{
+			TotalIncome: decimal(t.TotalIncome),
+			Tax:         decimal(t.Tax),
+			TaxRefund:   decimal(0),
+		}
+		if t.TaxRefund != nil {
+			upload.TaxRefund = decimal(*t.TaxRefund)
+		}
+		res.Taxes = append(res.Taxes, upload)
+	}
+	return res
+}
+
+func (h *Handler) TaxV2(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.TaxV2").End()
+
+	reqTax := TaxRequestV2{}
+	if err := c.Bind(&reqTax); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	year, err := taxYear(reqTax.TaxYear)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	res, status, err := h.calculate(c, &reqTax.TaxRequest)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, newTaxResponseV2(year, res))
+}
+
+func (h *Handler) HouseholdV2(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.HouseholdV2").End()
+
+	reqHousehold := HouseholdRequestV2{}
+	if err := c.Bind(&reqHousehold); err != nil {
+		return problem.JSON(c, problem.Bind(err))
+	}
+
+	year, err := taxYear(reqHousehold.TaxYear)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	res, status, err := h.household(c, &reqHousehold.HouseholdRequest)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, newHouseholdResponseV2(year, res))
+}
+
+// UploadCsvV2 reads the tax year from the taxYear form field.
+func (h *Handler) UploadCsvV2(c echo.Context) error {
+	defer tracing.Span(c, "tax.Handler.UploadCsvV2").End()
+
+	year := 0
+	if v := c.FormValue("taxYear"); v != "" {
+		var err error
+		year, err = strconv.Atoi(v)
+		if err != nil {
+			return problemJSON(c, http.StatusBadRequest, invalid(CodeInvalidTaxYear, "/taxYear", "type", "integer", "Invalid tax year"))
+		}
+	}
+
+	year, err := taxYear(year)
+	if err != nil {
+		return problemJSON(c, http.StatusBadRequest, err)
+	}
+
+	res, status, err := h.uploadCsv(c)
+	if err != nil {
+		return problemJSON(c, status, err)
+	}
+
+	return c.JSON(http.StatusOK, newTaxUploadResponseV2(year, res))
+}
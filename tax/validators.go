@@ -2,39 +2,69 @@ package tax
 
 import (
 	"fmt"
-	"net/http"
+	"reflect"
+	"strings"
 
+	"github.com/Gitong23/assessment-tax/problem"
 	"github.com/go-playground/validator"
-	"github.com/labstack/echo/v4"
 )
 
 type Validator struct {
 	validator *validator.Validate
 }
 
+// NewValidator names fields by their JSON names, so the field errors point
+// at the request the client sent.
 func NewValidator() *Validator {
-	return &Validator{validator: validator.New()}
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return &Validator{validator: v}
 }
 
+// Validate returns a problem with a field error for every rule i breaks.
 func (v *Validator) Validate(i interface{}) error {
 	if err := v.validator.Struct(i); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, Err{Message: "Invalid request body"})
+		return problem.Validation(err)
 	}
 	return nil
 }
 
 func (t *TaxRequest) validatWht() error {
-	if t.WHT > t.TotalIncome || t.WHT < 0 {
-		return fmt.Errorf("Invalid WHT value")
+	if t.WHT < 0 {
+		return invalid(CodeInvalidWHT, "/wht", "gte", "0", "Invalid WHT value")
+	}
+
+	if t.WHT > t.TotalIncome {
+		return invalid(CodeInvalidWHT, "/wht", "lte", "/totalIncome", "Invalid WHT value")
 	}
 	return nil
 }
 
+func (t *TaxRequest) validate() error {
+	err := t.validatWht()
+	if err != nil {
+		return err
+	}
+
+	err = t.validatePeriod()
+	if err != nil {
+		return err
+	}
+
+	return t.validateCredits()
+}
+
 func checkMultiWht(t []TaxRequest) error {
-	for _, taxReq := range t {
+	for i, taxReq := range t {
 		err := taxReq.validatWht()
 		if err != nil {
-			return err
+			return problem.Nest(err, fmt.Sprintf("/rows/%d", i))
 		}
 	}
 	return nil
@@ -0,0 +1,5 @@
+// Package taxpb is the gRPC API of the tax service, generated from
+// tax.proto.
+package taxpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative tax.proto
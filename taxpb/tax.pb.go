@@ -0,0 +1,2085 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: tax.proto
+
+package taxpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AllowanceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AllowanceType string  `protobuf:"bytes,1,opt,name=allowance_type,json=allowanceType,proto3" json:"allowance_type,omitempty"`
+	Amount        float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *AllowanceRequest) Reset() {
+	*x = AllowanceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AllowanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllowanceRequest) ProtoMessage() {}
+
+func (x *AllowanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllowanceRequest.ProtoReflect.Descriptor instead.
+func (*AllowanceRequest) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AllowanceRequest) GetAllowanceType() string {
+	if x != nil {
+		return x.AllowanceType
+	}
+	return ""
+}
+
+func (x *AllowanceRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type IncomeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Amount       float64 `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Wht          float64 `protobuf:"fixed64,2,opt,name=wht,proto3" json:"wht,omitempty"`
+	Currency     string  `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	ReceivedDate string  `protobuf:"bytes,4,opt,name=received_date,json=receivedDate,proto3" json:"received_date,omitempty"`
+}
+
+func (x *IncomeRequest) Reset() {
+	*x = IncomeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IncomeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncomeRequest) ProtoMessage() {}
+
+func (x *IncomeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncomeRequest.ProtoReflect.Descriptor instead.
+func (*IncomeRequest) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IncomeRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *IncomeRequest) GetWht() float64 {
+	if x != nil {
+		return x.Wht
+	}
+	return 0
+}
+
+func (x *IncomeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *IncomeRequest) GetReceivedDate() string {
+	if x != nil {
+		return x.ReceivedDate
+	}
+	return ""
+}
+
+type CreditRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CreditType string  `protobuf:"bytes,1,opt,name=credit_type,json=creditType,proto3" json:"credit_type,omitempty"`
+	Amount     float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Rate       float64 `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	TaxPaid    float64 `protobuf:"fixed64,4,opt,name=tax_paid,json=taxPaid,proto3" json:"tax_paid,omitempty"`
+}
+
+func (x *CreditRequest) Reset() {
+	*x = CreditRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreditRequest) ProtoMessage() {}
+
+func (x *CreditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreditRequest.ProtoReflect.Descriptor instead.
+func (*CreditRequest) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreditRequest) GetCreditType() string {
+	if x != nil {
+		return x.CreditType
+	}
+	return ""
+}
+
+func (x *CreditRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreditRequest) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *CreditRequest) GetTaxPaid() float64 {
+	if x != nil {
+		return x.TaxPaid
+	}
+	return 0
+}
+
+type TaxRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalIncome float64             `protobuf:"fixed64,1,opt,name=total_income,json=totalIncome,proto3" json:"total_income,omitempty"`
+	Wht         float64             `protobuf:"fixed64,2,opt,name=wht,proto3" json:"wht,omitempty"`
+	Allowances  []*AllowanceRequest `protobuf:"bytes,3,rep,name=allowances,proto3" json:"allowances,omitempty"`
+	Incomes     []*IncomeRequest    `protobuf:"bytes,4,rep,name=incomes,proto3" json:"incomes,omitempty"`
+	Period      string              `protobuf:"bytes,5,opt,name=period,proto3" json:"period,omitempty"`
+	HalfYearTax float64             `protobuf:"fixed64,6,opt,name=half_year_tax,json=halfYearTax,proto3" json:"half_year_tax,omitempty"`
+	FilingDate  string              `protobuf:"bytes,7,opt,name=filing_date,json=filingDate,proto3" json:"filing_date,omitempty"`
+	DueDate     string              `protobuf:"bytes,8,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	Penalty     string              `protobuf:"bytes,9,opt,name=penalty,proto3" json:"penalty,omitempty"`
+	Credits     []*CreditRequest    `protobuf:"bytes,10,rep,name=credits,proto3" json:"credits,omitempty"`
+	// tax_year defaults to the year before the current one and is echoed in
+	// the response. It doesn't select the tax levels.
+	TaxYear int32 `protobuf:"varint,11,opt,name=tax_year,json=taxYear,proto3" json:"tax_year,omitempty"`
+}
+
+func (x *TaxRequest) Reset() {
+	*x = TaxRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxRequest) ProtoMessage() {}
+
+func (x *TaxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxRequest.ProtoReflect.Descriptor instead.
+func (*TaxRequest) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TaxRequest) GetTotalIncome() float64 {
+	if x != nil {
+		return x.TotalIncome
+	}
+	return 0
+}
+
+func (x *TaxRequest) GetWht() float64 {
+	if x != nil {
+		return x.Wht
+	}
+	return 0
+}
+
+func (x *TaxRequest) GetAllowances() []*AllowanceRequest {
+	if x != nil {
+		return x.Allowances
+	}
+	return nil
+}
+
+func (x *TaxRequest) GetIncomes() []*IncomeRequest {
+	if x != nil {
+		return x.Incomes
+	}
+	return nil
+}
+
+func (x *TaxRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *TaxRequest) GetHalfYearTax() float64 {
+	if x != nil {
+		return x.HalfYearTax
+	}
+	return 0
+}
+
+func (x *TaxRequest) GetFilingDate() string {
+	if x != nil {
+		return x.FilingDate
+	}
+	return ""
+}
+
+func (x *TaxRequest) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+func (x *TaxRequest) GetPenalty() string {
+	if x != nil {
+		return x.Penalty
+	}
+	return ""
+}
+
+func (x *TaxRequest) GetCredits() []*CreditRequest {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+func (x *TaxRequest) GetTaxYear() int32 {
+	if x != nil {
+		return x.TaxYear
+	}
+	return 0
+}
+
+type TaxSummary struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TotalIncome float64 `protobuf:"fixed64,1,opt,name=total_income,json=totalIncome,proto3" json:"total_income,omitempty"`
+	Allowances  float64 `protobuf:"fixed64,2,opt,name=allowances,proto3" json:"allowances,omitempty"`
+	NetIncome   float64 `protobuf:"fixed64,3,opt,name=net_income,json=netIncome,proto3" json:"net_income,omitempty"`
+	LevelTax    float64 `protobuf:"fixed64,4,opt,name=level_tax,json=levelTax,proto3" json:"level_tax,omitempty"`
+	TaxPaid     float64 `protobuf:"fixed64,5,opt,name=tax_paid,json=taxPaid,proto3" json:"tax_paid,omitempty"`
+	Credits     float64 `protobuf:"fixed64,6,opt,name=credits,proto3" json:"credits,omitempty"`
+}
+
+func (x *TaxSummary) Reset() {
+	*x = TaxSummary{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaxSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxSummary) ProtoMessage() {}
+
+func (x *TaxSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxSummary.ProtoReflect.Descriptor instead.
+func (*TaxSummary) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TaxSummary) GetTotalIncome() float64 {
+	if x != nil {
+		return x.TotalIncome
+	}
+	return 0
+}
+
+func (x *TaxSummary) GetAllowances() float64 {
+	if x != nil {
+		return x.Allowances
+	}
+	return 0
+}
+
+func (x *TaxSummary) GetNetIncome() float64 {
+	if x != nil {
+		return x.NetIncome
+	}
+	return 0
+}
+
+func (x *TaxSummary) GetLevelTax() float64 {
+	if x != nil {
+		return x.LevelTax
+	}
+	return 0
+}
+
+func (x *TaxSummary) GetTaxPaid() float64 {
+	if x != nil {
+		return x.TaxPaid
+	}
+	return 0
+}
+
+func (x *TaxSummary) GetCredits() float64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+type TaxLevel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Level string  `protobuf:"bytes,1,opt,name=level,proto3" json:"level,omitempty"`
+	Tax   float64 `protobuf:"fixed64,2,opt,name=tax,proto3" json:"tax,omitempty"`
+}
+
+func (x *TaxLevel) Reset() {
+	*x = TaxLevel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaxLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxLevel) ProtoMessage() {}
+
+func (x *TaxLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxLevel.ProtoReflect.Descriptor instead.
+func (*TaxLevel) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TaxLevel) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *TaxLevel) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+type Credit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CreditType string  `protobuf:"bytes,1,opt,name=credit_type,json=creditType,proto3" json:"credit_type,omitempty"`
+	Amount     float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Applied    float64 `protobuf:"fixed64,3,opt,name=applied,proto3" json:"applied,omitempty"`
+	Unused     float64 `protobuf:"fixed64,4,opt,name=unused,proto3" json:"unused,omitempty"`
+}
+
+func (x *Credit) Reset() {
+	*x = Credit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Credit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Credit) ProtoMessage() {}
+
+func (x *Credit) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Credit.ProtoReflect.Descriptor instead.
+func (*Credit) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Credit) GetCreditType() string {
+	if x != nil {
+		return x.CreditType
+	}
+	return ""
+}
+
+func (x *Credit) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Credit) GetApplied() float64 {
+	if x != nil {
+		return x.Applied
+	}
+	return 0
+}
+
+func (x *Credit) GetUnused() float64 {
+	if x != nil {
+		return x.Unused
+	}
+	return 0
+}
+
+type SurchargeMonth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Month  int32   `protobuf:"varint,1,opt,name=month,proto3" json:"month,omitempty"`
+	Amount float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *SurchargeMonth) Reset() {
+	*x = SurchargeMonth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SurchargeMonth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SurchargeMonth) ProtoMessage() {}
+
+func (x *SurchargeMonth) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SurchargeMonth.ProtoReflect.Descriptor instead.
+func (*SurchargeMonth) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SurchargeMonth) GetMonth() int32 {
+	if x != nil {
+		return x.Month
+	}
+	return 0
+}
+
+func (x *SurchargeMonth) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type Surcharge struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Months    int32             `protobuf:"varint,1,opt,name=months,proto3" json:"months,omitempty"`
+	Amount    float64           `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Penalty   float64           `protobuf:"fixed64,3,opt,name=penalty,proto3" json:"penalty,omitempty"`
+	Total     float64           `protobuf:"fixed64,4,opt,name=total,proto3" json:"total,omitempty"`
+	Breakdown []*SurchargeMonth `protobuf:"bytes,5,rep,name=breakdown,proto3" json:"breakdown,omitempty"`
+}
+
+func (x *Surcharge) Reset() {
+	*x = Surcharge{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Surcharge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Surcharge) ProtoMessage() {}
+
+func (x *Surcharge) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Surcharge.ProtoReflect.Descriptor instead.
+func (*Surcharge) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Surcharge) GetMonths() int32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *Surcharge) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Surcharge) GetPenalty() float64 {
+	if x != nil {
+		return x.Penalty
+	}
+	return 0
+}
+
+func (x *Surcharge) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Surcharge) GetBreakdown() []*SurchargeMonth {
+	if x != nil {
+		return x.Breakdown
+	}
+	return nil
+}
+
+type Conversion struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Currency     string  `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	ReceivedDate string  `protobuf:"bytes,2,opt,name=received_date,json=receivedDate,proto3" json:"received_date,omitempty"`
+	RateDate     string  `protobuf:"bytes,3,opt,name=rate_date,json=rateDate,proto3" json:"rate_date,omitempty"`
+	Rate         float64 `protobuf:"fixed64,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	Amount       float64 `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Wht          float64 `protobuf:"fixed64,6,opt,name=wht,proto3" json:"wht,omitempty"`
+	AmountThb    float64 `protobuf:"fixed64,7,opt,name=amount_thb,json=amountThb,proto3" json:"amount_thb,omitempty"`
+	WhtThb       float64 `protobuf:"fixed64,8,opt,name=wht_thb,json=whtThb,proto3" json:"wht_thb,omitempty"`
+}
+
+func (x *Conversion) Reset() {
+	*x = Conversion{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Conversion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Conversion) ProtoMessage() {}
+
+func (x *Conversion) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Conversion.ProtoReflect.Descriptor instead.
+func (*Conversion) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Conversion) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Conversion) GetReceivedDate() string {
+	if x != nil {
+		return x.ReceivedDate
+	}
+	return ""
+}
+
+func (x *Conversion) GetRateDate() string {
+	if x != nil {
+		return x.RateDate
+	}
+	return ""
+}
+
+func (x *Conversion) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *Conversion) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Conversion) GetWht() float64 {
+	if x != nil {
+		return x.Wht
+	}
+	return 0
+}
+
+func (x *Conversion) GetAmountThb() float64 {
+	if x != nil {
+		return x.AmountThb
+	}
+	return 0
+}
+
+func (x *Conversion) GetWhtThb() float64 {
+	if x != nil {
+		return x.WhtThb
+	}
+	return 0
+}
+
+type TaxResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TaxYear   int32       `protobuf:"varint,1,opt,name=tax_year,json=taxYear,proto3" json:"tax_year,omitempty"`
+	Tax       float64     `protobuf:"fixed64,2,opt,name=tax,proto3" json:"tax,omitempty"`
+	TaxRefund float64     `protobuf:"fixed64,3,opt,name=tax_refund,json=taxRefund,proto3" json:"tax_refund,omitempty"`
+	Summary   *TaxSummary `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	TaxLevels []*TaxLevel `protobuf:"bytes,5,rep,name=tax_levels,json=taxLevels,proto3" json:"tax_levels,omitempty"`
+	Credits   []*Credit   `protobuf:"bytes,6,rep,name=credits,proto3" json:"credits,omitempty"`
+	// surcharge is only set for late filings.
+	Surcharge   *Surcharge    `protobuf:"bytes,7,opt,name=surcharge,proto3" json:"surcharge,omitempty"`
+	Conversions []*Conversion `protobuf:"bytes,8,rep,name=conversions,proto3" json:"conversions,omitempty"`
+}
+
+func (x *TaxResponse) Reset() {
+	*x = TaxResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TaxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxResponse) ProtoMessage() {}
+
+func (x *TaxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxResponse.ProtoReflect.Descriptor instead.
+func (*TaxResponse) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TaxResponse) GetTaxYear() int32 {
+	if x != nil {
+		return x.TaxYear
+	}
+	return 0
+}
+
+func (x *TaxResponse) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *TaxResponse) GetTaxRefund() float64 {
+	if x != nil {
+		return x.TaxRefund
+	}
+	return 0
+}
+
+func (x *TaxResponse) GetSummary() *TaxSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *TaxResponse) GetTaxLevels() []*TaxLevel {
+	if x != nil {
+		return x.TaxLevels
+	}
+	return nil
+}
+
+func (x *TaxResponse) GetCredits() []*Credit {
+	if x != nil {
+		return x.Credits
+	}
+	return nil
+}
+
+func (x *TaxResponse) GetSurcharge() *Surcharge {
+	if x != nil {
+		return x.Surcharge
+	}
+	return nil
+}
+
+func (x *TaxResponse) GetConversions() []*Conversion {
+	if x != nil {
+		return x.Conversions
+	}
+	return nil
+}
+
+// Problem is why a request can't be answered, with the code and field
+// errors of the REST problem details.
+type Problem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code    string        `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string        `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Errors  []*FieldError `protobuf:"bytes,3,rep,name=errors,proto3" json:"errors,omitempty"`
+}
+
+func (x *Problem) Reset() {
+	*x = Problem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Problem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Problem) ProtoMessage() {}
+
+func (x *Problem) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Problem.ProtoReflect.Descriptor instead.
+func (*Problem) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Problem) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Problem) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Problem) GetErrors() []*FieldError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type FieldError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pointer string `protobuf:"bytes,1,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	Rule    string `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	Limit   string `protobuf:"bytes,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *FieldError) Reset() {
+	*x = FieldError{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldError) ProtoMessage() {}
+
+func (x *FieldError) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldError.ProtoReflect.Descriptor instead.
+func (*FieldError) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FieldError) GetPointer() string {
+	if x != nil {
+		return x.Pointer
+	}
+	return ""
+}
+
+func (x *FieldError) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *FieldError) GetLimit() string {
+	if x != nil {
+		return x.Limit
+	}
+	return ""
+}
+
+func (x *FieldError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// index is the position of the request in the stream, from 0.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Types that are assignable to Result:
+	//	*BatchResult_Response
+	//	*BatchResult_Problem
+	Result isBatchResult_Result `protobuf_oneof:"result"`
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (m *BatchResult) GetResult() isBatchResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *BatchResult) GetResponse() *TaxResponse {
+	if x, ok := x.GetResult().(*BatchResult_Response); ok {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *BatchResult) GetProblem() *Problem {
+	if x, ok := x.GetResult().(*BatchResult_Problem); ok {
+		return x.Problem
+	}
+	return nil
+}
+
+type isBatchResult_Result interface {
+	isBatchResult_Result()
+}
+
+type BatchResult_Response struct {
+	Response *TaxResponse `protobuf:"bytes,2,opt,name=response,proto3,oneof"`
+}
+
+type BatchResult_Problem struct {
+	Problem *Problem `protobuf:"bytes,3,opt,name=problem,proto3,oneof"`
+}
+
+func (*BatchResult_Response) isBatchResult_Result() {}
+
+func (*BatchResult_Problem) isBatchResult_Result() {}
+
+type GetDeductionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetDeductionsRequest) Reset() {
+	*x = GetDeductionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDeductionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeductionsRequest) ProtoMessage() {}
+
+func (x *GetDeductionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeductionsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeductionsRequest) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{14}
+}
+
+type Deduction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id             int32   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type           string  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	InitAmount     float64 `protobuf:"fixed64,3,opt,name=init_amount,json=initAmount,proto3" json:"init_amount,omitempty"`
+	MinAmount      float64 `protobuf:"fixed64,4,opt,name=min_amount,json=minAmount,proto3" json:"min_amount,omitempty"`
+	MaxAmount      float64 `protobuf:"fixed64,5,opt,name=max_amount,json=maxAmount,proto3" json:"max_amount,omitempty"`
+	LimitMaxAmount float64 `protobuf:"fixed64,6,opt,name=limit_max_amount,json=limitMaxAmount,proto3" json:"limit_max_amount,omitempty"`
+	CreatedAt      string  `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Deduction) Reset() {
+	*x = Deduction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Deduction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Deduction) ProtoMessage() {}
+
+func (x *Deduction) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Deduction.ProtoReflect.Descriptor instead.
+func (*Deduction) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Deduction) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Deduction) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Deduction) GetInitAmount() float64 {
+	if x != nil {
+		return x.InitAmount
+	}
+	return 0
+}
+
+func (x *Deduction) GetMinAmount() float64 {
+	if x != nil {
+		return x.MinAmount
+	}
+	return 0
+}
+
+func (x *Deduction) GetMaxAmount() float64 {
+	if x != nil {
+		return x.MaxAmount
+	}
+	return 0
+}
+
+func (x *Deduction) GetLimitMaxAmount() float64 {
+	if x != nil {
+		return x.LimitMaxAmount
+	}
+	return 0
+}
+
+func (x *Deduction) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetDeductionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deductions []*Deduction `protobuf:"bytes,1,rep,name=deductions,proto3" json:"deductions,omitempty"`
+}
+
+func (x *GetDeductionsResponse) Reset() {
+	*x = GetDeductionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetDeductionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeductionsResponse) ProtoMessage() {}
+
+func (x *GetDeductionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeductionsResponse.ProtoReflect.Descriptor instead.
+func (*GetDeductionsResponse) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetDeductionsResponse) GetDeductions() []*Deduction {
+	if x != nil {
+		return x.Deductions
+	}
+	return nil
+}
+
+type UpdateDeductionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type is "personal" or "k-receipt".
+	Type   string  `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Amount float64 `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+}
+
+func (x *UpdateDeductionRequest) Reset() {
+	*x = UpdateDeductionRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateDeductionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeductionRequest) ProtoMessage() {}
+
+func (x *UpdateDeductionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeductionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDeductionRequest) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateDeductionRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *UpdateDeductionRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type DeductionChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          int32   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type        string  `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Amount      float64 `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status      string  `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	SubmittedBy string  `protobuf:"bytes,5,opt,name=submitted_by,json=submittedBy,proto3" json:"submitted_by,omitempty"`
+	CreatedAt   string  `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *DeductionChange) Reset() {
+	*x = DeductionChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeductionChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeductionChange) ProtoMessage() {}
+
+func (x *DeductionChange) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeductionChange.ProtoReflect.Descriptor instead.
+func (*DeductionChange) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeductionChange) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeductionChange) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DeductionChange) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *DeductionChange) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeductionChange) GetSubmittedBy() string {
+	if x != nil {
+		return x.SubmittedBy
+	}
+	return ""
+}
+
+func (x *DeductionChange) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type UpdateDeductionResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Result:
+	//	*UpdateDeductionResponse_Deduction
+	//	*UpdateDeductionResponse_Change
+	Result isUpdateDeductionResponse_Result `protobuf_oneof:"result"`
+}
+
+func (x *UpdateDeductionResponse) Reset() {
+	*x = UpdateDeductionResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tax_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateDeductionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDeductionResponse) ProtoMessage() {}
+
+func (x *UpdateDeductionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tax_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDeductionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateDeductionResponse) Descriptor() ([]byte, []int) {
+	return file_tax_proto_rawDescGZIP(), []int{19}
+}
+
+func (m *UpdateDeductionResponse) GetResult() isUpdateDeductionResponse_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *UpdateDeductionResponse) GetDeduction() *Deduction {
+	if x, ok := x.GetResult().(*UpdateDeductionResponse_Deduction); ok {
+		return x.Deduction
+	}
+	return nil
+}
+
+func (x *UpdateDeductionResponse) GetChange() *DeductionChange {
+	if x, ok := x.GetResult().(*UpdateDeductionResponse_Change); ok {
+		return x.Change
+	}
+	return nil
+}
+
+type isUpdateDeductionResponse_Result interface {
+	isUpdateDeductionResponse_Result()
+}
+
+type UpdateDeductionResponse_Deduction struct {
+	// deduction is the allowance once the change applied.
+	Deduction *Deduction `protobuf:"bytes,1,opt,name=deduction,proto3,oneof"`
+}
+
+type UpdateDeductionResponse_Change struct {
+	// change is the change waiting for approval.
+	Change *DeductionChange `protobuf:"bytes,2,opt,name=change,proto3,oneof"`
+}
+
+func (*UpdateDeductionResponse_Deduction) isUpdateDeductionResponse_Result() {}
+
+func (*UpdateDeductionResponse_Change) isUpdateDeductionResponse_Result() {}
+
+var File_tax_proto protoreflect.FileDescriptor
+
+var file_tax_proto_rawDesc = []byte{
+	0x0a, 0x09, 0x74, 0x61, 0x78, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6b, 0x74, 0x61,
+	0x78, 0x2e, 0x76, 0x31, 0x22, 0x51, 0x0a, 0x10, 0x41, 0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x7a, 0x0a, 0x0d, 0x49, 0x6e, 0x63, 0x6f, 0x6d,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x77, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x77,
+	0x68, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x23,
+	0x0a, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x44,
+	0x61, 0x74, 0x65, 0x22, 0x77, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x64, 0x69, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x64, 0x69,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x07, 0x74, 0x61, 0x78, 0x50, 0x61, 0x69, 0x64, 0x22, 0x8d, 0x03, 0x0a,
+	0x0a, 0x54, 0x61, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x77, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x77, 0x68, 0x74,
+	0x12, 0x39, 0x0a, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x69,
+	0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6b,
+	0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x73, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x79, 0x65,
+	0x61, 0x72, 0x5f, 0x74, 0x61, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x68, 0x61,
+	0x6c, 0x66, 0x59, 0x65, 0x61, 0x72, 0x54, 0x61, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x69, 0x6c,
+	0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x66, 0x69, 0x6c, 0x69, 0x6e, 0x67, 0x44, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x64, 0x75,
+	0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x75,
+	0x65, 0x44, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x12,
+	0x30, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x64, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74,
+	0x73, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x78, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x74, 0x61, 0x78, 0x59, 0x65, 0x61, 0x72, 0x22, 0xc0, 0x01, 0x0a,
+	0x0a, 0x54, 0x61, 0x78, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0a, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x1d,
+	0x0a, 0x0a, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x09, 0x6e, 0x65, 0x74, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x5f, 0x74, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x08, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x54, 0x61, 0x78, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x61,
+	0x78, 0x5f, 0x70, 0x61, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x74, 0x61,
+	0x78, 0x50, 0x61, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x22,
+	0x32, 0x0a, 0x08, 0x54, 0x61, 0x78, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x6c,
+	0x65, 0x76, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65,
+	0x6c, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x74, 0x61, 0x78, 0x22, 0x73, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x64, 0x69, 0x74, 0x12, 0x1f, 0x0a,
+	0x0b, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x75, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x75, 0x6e, 0x75, 0x73, 0x65, 0x64, 0x22, 0x3e, 0x0a, 0x0e, 0x53, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f,
+	0x6e, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6d, 0x6f, 0x6e, 0x74, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xa2, 0x01, 0x0a, 0x09, 0x53, 0x75, 0x72,
+	0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6d, 0x6f, 0x6e, 0x74, 0x68, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x35, 0x0a, 0x09, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x64,
+	0x6f, 0x77, 0x6e, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x74, 0x61, 0x78,
+	0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x4d, 0x6f, 0x6e,
+	0x74, 0x68, 0x52, 0x09, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x64, 0x6f, 0x77, 0x6e, 0x22, 0xe0, 0x01,
+	0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x44, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a,
+	0x09, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x72, 0x61, 0x74, 0x65, 0x44, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x61,
+	0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x77, 0x68, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x03, 0x77, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x5f, 0x74, 0x68, 0x62, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x61, 0x6d,
+	0x6f, 0x75, 0x6e, 0x74, 0x54, 0x68, 0x62, 0x12, 0x17, 0x0a, 0x07, 0x77, 0x68, 0x74, 0x5f, 0x74,
+	0x68, 0x62, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x68, 0x74, 0x54, 0x68, 0x62,
+	0x22, 0xce, 0x02, 0x0a, 0x0b, 0x54, 0x61, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x19, 0x0a, 0x08, 0x74, 0x61, 0x78, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x74, 0x61, 0x78, 0x59, 0x65, 0x61, 0x72, 0x12, 0x10, 0x0a, 0x03, 0x74,
+	0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x74, 0x61, 0x78, 0x12, 0x1d, 0x0a,
+	0x0a, 0x74, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x09, 0x74, 0x61, 0x78, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x12, 0x2d, 0x0a, 0x07,
+	0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e,
+	0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x78, 0x53, 0x75, 0x6d, 0x6d, 0x61,
+	0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x30, 0x0a, 0x0a, 0x74,
+	0x61, 0x78, 0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x78, 0x4c, 0x65, 0x76,
+	0x65, 0x6c, 0x52, 0x09, 0x74, 0x61, 0x78, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x12, 0x29, 0x0a,
+	0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
+	0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x64, 0x69, 0x74, 0x52,
+	0x07, 0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x12, 0x30, 0x0a, 0x09, 0x73, 0x75, 0x72, 0x63,
+	0x68, 0x61, 0x72, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x74,
+	0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x52,
+	0x09, 0x73, 0x75, 0x72, 0x63, 0x68, 0x61, 0x72, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x0b, 0x63, 0x6f,
+	0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x13, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0x64, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x06, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6b, 0x74, 0x61,
+	0x78, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x06, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x22, 0x6a, 0x0a, 0x0a, 0x46, 0x69, 0x65, 0x6c, 0x64,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x72, 0x75, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72,
+	0x75, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x22, 0x8f, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x32, 0x0a, 0x08, 0x72, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6b, 0x74,
+	0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a,
+	0x07, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d,
+	0x48, 0x00, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x62, 0x6c, 0x65, 0x6d, 0x42, 0x08, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x16, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x44, 0x65, 0x64, 0x75,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xd7, 0x01,
+	0x0a, 0x09, 0x44, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x69, 0x6e, 0x69, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x6d, 0x69, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x28,
+	0x0a, 0x10, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x4d,
+	0x61, 0x78, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x4b, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x44, 0x65,
+	0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x32, 0x0a, 0x0a, 0x64, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0a, 0x64, 0x65, 0x64, 0x75, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x44, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65,
+	0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xa7, 0x01, 0x0a, 0x0f, 0x44,
+	0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x5f,
+	0x62, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x74, 0x65, 0x64, 0x42, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
+	0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x22, 0x8b, 0x01, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44,
+	0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x32, 0x0a, 0x09, 0x64, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x09, 0x64, 0x65, 0x64, 0x75, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x32, 0x0a, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x32, 0xae, 0x02, 0x0a, 0x0a, 0x54, 0x61, 0x78, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x39, 0x0a, 0x0c, 0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x54, 0x61,
+	0x78, 0x12, 0x13, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x78, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x61, 0x78, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0e,
+	0x43, 0x61, 0x6c, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x13,
+	0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x78, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x28, 0x01, 0x30, 0x01, 0x12, 0x4e, 0x0a,
+	0x0d, 0x47, 0x65, 0x74, 0x44, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1d,
+	0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x64, 0x75,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e,
+	0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x64, 0x75, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x54, 0x0a,
+	0x0f, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x44, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x1f, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x44, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x20, 0x2e, 0x6b, 0x74, 0x61, 0x78, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x44, 0x65, 0x64, 0x75, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f,
+	0x6d, 0x2f, 0x47, 0x69, 0x74, 0x6f, 0x6e, 0x67, 0x32, 0x33, 0x2f, 0x61, 0x73, 0x73, 0x65, 0x73,
+	0x73, 0x6d, 0x65, 0x6e, 0x74, 0x2d, 0x74, 0x61, 0x78, 0x2f, 0x74, 0x61, 0x78, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_tax_proto_rawDescOnce sync.Once
+	file_tax_proto_rawDescData = file_tax_proto_rawDesc
+)
+
+func file_tax_proto_rawDescGZIP() []byte {
+	file_tax_proto_rawDescOnce.Do(func() {
+		file_tax_proto_rawDescData = protoimpl.X.CompressGZIP(file_tax_proto_rawDescData)
+	})
+	return file_tax_proto_rawDescData
+}
+
+var file_tax_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_tax_proto_goTypes = []any{
+	(*AllowanceRequest)(nil),        // 0: ktax.v1.AllowanceRequest
+	(*IncomeRequest)(nil),           // 1: ktax.v1.IncomeRequest
+	(*CreditRequest)(nil),           // 2: ktax.v1.CreditRequest
+	(*TaxRequest)(nil),              // 3: ktax.v1.TaxRequest
+	(*TaxSummary)(nil),              // 4: ktax.v1.TaxSummary
+	(*TaxLevel)(nil),                // 5: ktax.v1.TaxLevel
+	(*Credit)(nil),                  // 6: ktax.v1.Credit
+	(*SurchargeMonth)(nil),          // 7: ktax.v1.SurchargeMonth
+	(*Surcharge)(nil),               // 8: ktax.v1.Surcharge
+	(*Conversion)(nil),              // 9: ktax.v1.Conversion
+	(*TaxResponse)(nil),             // 10: ktax.v1.TaxResponse
+	(*Problem)(nil),                 // 11: ktax.v1.Problem
+	(*FieldError)(nil),              // 12: ktax.v1.FieldError
+	(*BatchResult)(nil),             // 13: ktax.v1.BatchResult
+	(*GetDeductionsRequest)(nil),    // 14: ktax.v1.GetDeductionsRequest
+	(*Deduction)(nil),               // 15: ktax.v1.Deduction
+	(*GetDeductionsResponse)(nil),   // 16: ktax.v1.GetDeductionsResponse
+	(*UpdateDeductionRequest)(nil),  // 17: ktax.v1.UpdateDeductionRequest
+	(*DeductionChange)(nil),         // 18: ktax.v1.DeductionChange
+	(*UpdateDeductionResponse)(nil), // 19: ktax.v1.UpdateDeductionResponse
+}
+var file_tax_proto_depIdxs = []int32{
+	0,  // 0: ktax.v1.TaxRequest.allowances:type_name -> ktax.v1.AllowanceRequest
+	1,  // 1: ktax.v1.TaxRequest.incomes:type_name -> ktax.v1.IncomeRequest
+	2,  // 2: ktax.v1.TaxRequest.credits:type_name -> ktax.v1.CreditRequest
+	7,  // 3: ktax.v1.Surcharge.breakdown:type_name -> ktax.v1.SurchargeMonth
+	4,  // 4: ktax.v1.TaxResponse.summary:type_name -> ktax.v1.TaxSummary
+	5,  // 5: ktax.v1.TaxResponse.tax_levels:type_name -> ktax.v1.TaxLevel
+	6,  // 6: ktax.v1.TaxResponse.credits:type_name -> ktax.v1.Credit
+	8,  // 7: ktax.v1.TaxResponse.surcharge:type_name -> ktax.v1.Surcharge
+	9,  // 8: ktax.v1.TaxResponse.conversions:type_name -> ktax.v1.Conversion
+	12, // 9: ktax.v1.Problem.errors:type_name -> ktax.v1.FieldError
+	10, // 10: ktax.v1.BatchResult.response:type_name -> ktax.v1.TaxResponse
+	11, // 11: ktax.v1.BatchResult.problem:type_name -> ktax.v1.Problem
+	15, // 12: ktax.v1.GetDeductionsResponse.deductions:type_name -> ktax.v1.Deduction
+	15, // 13: ktax.v1.UpdateDeductionResponse.deduction:type_name -> ktax.v1.Deduction
+	18, // 14: ktax.v1.UpdateDeductionResponse.change:type_name -> ktax.v1.DeductionChange
+	3,  // 15: ktax.v1.TaxService.CalculateTax:input_type -> ktax.v1.TaxRequest
+	3,  // 16: ktax.v1.TaxService.CalculateBatch:input_type -> ktax.v1.TaxRequest
+	14, // 17: ktax.v1.TaxService.GetDeductions:input_type -> ktax.v1.GetDeductionsRequest
+	17, // 18: ktax.v1.TaxService.UpdateDeduction:input_type -> ktax.v1.UpdateDeductionRequest
+	10, // 19: ktax.v1.TaxService.CalculateTax:output_type -> ktax.v1.TaxResponse
+	13, // 20: ktax.v1.TaxService.CalculateBatch:output_type -> ktax.v1.BatchResult
+	16, // 21: ktax.v1.TaxService.GetDeductions:output_type -> ktax.v1.GetDeductionsResponse
+	19, // 22: ktax.v1.TaxService.UpdateDeduction:output_type -> ktax.v1.UpdateDeductionResponse
+	19, // [19:23] is the sub-list for method output_type
+	15, // [15:19] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_tax_proto_init() }
+func file_tax_proto_init() {
+	if File_tax_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_tax_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*AllowanceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*IncomeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CreditRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*TaxRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*TaxSummary); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*TaxLevel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*Credit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*SurchargeMonth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*Surcharge); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Conversion); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*TaxResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*Problem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*FieldError); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*BatchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDeductionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*Deduction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*GetDeductionsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateDeductionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*DeductionChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_tax_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateDeductionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_tax_proto_msgTypes[13].OneofWrappers = []any{
+		(*BatchResult_Response)(nil),
+		(*BatchResult_Problem)(nil),
+	}
+	file_tax_proto_msgTypes[19].OneofWrappers = []any{
+		(*UpdateDeductionResponse_Deduction)(nil),
+		(*UpdateDeductionResponse_Change)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_tax_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_tax_proto_goTypes,
+		DependencyIndexes: file_tax_proto_depIdxs,
+		MessageInfos:      file_tax_proto_msgTypes,
+	}.Build()
+	File_tax_proto = out.File
+	file_tax_proto_rawDesc = nil
+	file_tax_proto_goTypes = nil
+	file_tax_proto_depIdxs = nil
+}
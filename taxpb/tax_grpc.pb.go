@@ -0,0 +1,273 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: tax.proto
+
+package taxpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	TaxService_CalculateTax_FullMethodName    = "/ktax.v1.TaxService/CalculateTax"
+	TaxService_CalculateBatch_FullMethodName  = "/ktax.v1.TaxService/CalculateBatch"
+	TaxService_GetDeductions_FullMethodName   = "/ktax.v1.TaxService/GetDeductions"
+	TaxService_UpdateDeduction_FullMethodName = "/ktax.v1.TaxService/UpdateDeduction"
+)
+
+// TaxServiceClient is the client API for TaxService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TaxService calculates taxes and manages the allowances they're calculated
+// with. It answers the same way as the v2 REST API.
+type TaxServiceClient interface {
+	CalculateTax(ctx context.Context, in *TaxRequest, opts ...grpc.CallOption) (*TaxResponse, error)
+	// CalculateBatch answers every request as soon as it's calculated, in the
+	// order they're sent. A request that can't be calculated is answered
+	// with its problem and doesn't end the stream.
+	CalculateBatch(ctx context.Context, opts ...grpc.CallOption) (TaxService_CalculateBatchClient, error)
+	GetDeductions(ctx context.Context, in *GetDeductionsRequest, opts ...grpc.CallOption) (*GetDeductionsResponse, error)
+	// UpdateDeduction submits the change for approval when deduction changes
+	// need to be approved, and applies it otherwise.
+	UpdateDeduction(ctx context.Context, in *UpdateDeductionRequest, opts ...grpc.CallOption) (*UpdateDeductionResponse, error)
+}
+
+type taxServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaxServiceClient(cc grpc.ClientConnInterface) TaxServiceClient {
+	return &taxServiceClient{cc}
+}
+
+func (c *taxServiceClient) CalculateTax(ctx context.Context, in *TaxRequest, opts ...grpc.CallOption) (*TaxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaxResponse)
+	err := c.cc.Invoke(ctx, TaxService_CalculateTax_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxServiceClient) CalculateBatch(ctx context.Context, opts ...grpc.CallOption) (TaxService_CalculateBatchClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TaxService_ServiceDesc.Streams[0], TaxService_CalculateBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &taxServiceCalculateBatchClient{ClientStream: stream}
+	return x, nil
+}
+
+type TaxService_CalculateBatchClient interface {
+	Send(*TaxRequest) error
+	Recv() (*BatchResult, error)
+	grpc.ClientStream
+}
+
+type taxServiceCalculateBatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *taxServiceCalculateBatchClient) Send(m *TaxRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *taxServiceCalculateBatchClient) Recv() (*BatchResult, error) {
+	m := new(BatchResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *taxServiceClient) GetDeductions(ctx context.Context, in *GetDeductionsRequest, opts ...grpc.CallOption) (*GetDeductionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeductionsResponse)
+	err := c.cc.Invoke(ctx, TaxService_GetDeductions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taxServiceClient) UpdateDeduction(ctx context.Context, in *UpdateDeductionRequest, opts ...grpc.CallOption) (*UpdateDeductionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateDeductionResponse)
+	err := c.cc.Invoke(ctx, TaxService_UpdateDeduction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TaxServiceServer is the server API for TaxService service.
+// All implementations must embed UnimplementedTaxServiceServer
+// for forward compatibility
+//
+// TaxService calculates taxes and manages the allowances they're calculated
+// with. It answers the same way as the v2 REST API.
+type TaxServiceServer interface {
+	CalculateTax(context.Context, *TaxRequest) (*TaxResponse, error)
+	// CalculateBatch answers every request as soon as it's calculated, in the
+	// order they're sent. A request that can't be calculated is answered
+	// with its problem and doesn't end the stream.
+	CalculateBatch(TaxService_CalculateBatchServer) error
+	GetDeductions(context.Context, *GetDeductionsRequest) (*GetDeductionsResponse, error)
+	// UpdateDeduction submits the change for approval when deduction changes
+	// need to be approved, and applies it otherwise.
+	UpdateDeduction(context.Context, *UpdateDeductionRequest) (*UpdateDeductionResponse, error)
+	mustEmbedUnimplementedTaxServiceServer()
+}
+
+// UnimplementedTaxServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTaxServiceServer struct {
+}
+
+func (UnimplementedTaxServiceServer) CalculateTax(context.Context, *TaxRequest) (*TaxResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CalculateTax not implemented")
+}
+func (UnimplementedTaxServiceServer) CalculateBatch(TaxService_CalculateBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method CalculateBatch not implemented")
+}
+func (UnimplementedTaxServiceServer) GetDeductions(context.Context, *GetDeductionsRequest) (*GetDeductionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeductions not implemented")
+}
+func (UnimplementedTaxServiceServer) UpdateDeduction(context.Context, *UpdateDeductionRequest) (*UpdateDeductionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDeduction not implemented")
+}
+func (UnimplementedTaxServiceServer) mustEmbedUnimplementedTaxServiceServer() {}
+
+// UnsafeTaxServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaxServiceServer will
+// result in compilation errors.
+type UnsafeTaxServiceServer interface {
+	mustEmbedUnimplementedTaxServiceServer()
+}
+
+func RegisterTaxServiceServer(s grpc.ServiceRegistrar, srv TaxServiceServer) {
+	s.RegisterService(&TaxService_ServiceDesc, srv)
+}
+
+func _TaxService_CalculateTax_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxServiceServer).CalculateTax(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaxService_CalculateTax_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxServiceServer).CalculateTax(ctx, req.(*TaxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaxService_CalculateBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TaxServiceServer).CalculateBatch(&taxServiceCalculateBatchServer{ServerStream: stream})
+}
+
+type TaxService_CalculateBatchServer interface {
+	Send(*BatchResult) error
+	Recv() (*TaxRequest, error)
+	grpc.ServerStream
+}
+
+type taxServiceCalculateBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *taxServiceCalculateBatchServer) Send(m *BatchResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *taxServiceCalculateBatchServer) Recv() (*TaxRequest, error) {
+	m := new(TaxRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TaxService_GetDeductions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeductionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxServiceServer).GetDeductions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaxService_GetDeductions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxServiceServer).GetDeductions(ctx, req.(*GetDeductionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaxService_UpdateDeduction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDeductionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaxServiceServer).UpdateDeduction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaxService_UpdateDeduction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaxServiceServer).UpdateDeduction(ctx, req.(*UpdateDeductionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TaxService_ServiceDesc is the grpc.ServiceDesc for TaxService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaxService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ktax.v1.TaxService",
+	HandlerType: (*TaxServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CalculateTax",
+			Handler:    _TaxService_CalculateTax_Handler,
+		},
+		{
+			MethodName: "GetDeductions",
+			Handler:    _TaxService_GetDeductions_Handler,
+		},
+		{
+			MethodName: "UpdateDeduction",
+			Handler:    _TaxService_UpdateDeduction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CalculateBatch",
+			Handler:       _TaxService_CalculateBatch_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "tax.proto",
+}
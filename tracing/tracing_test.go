@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	e := echo.New()
+	e.Use(Middleware)
+	e.POST("/tax/calculations", func(c echo.Context) error {
+		defer Span(c, "tax.Handler.Tax").End()
+
+		_, span := Start(c.Request().Context(), "postgres.PersonalAllowance")
+		span.End()
+		return c.NoContent(http.StatusOK)
+	})
+
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/tax/calculations", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	wantNames := []string{"postgres.PersonalAllowance", "tax.Handler.Tax", "POST /tax/calculations"}
+	if len(spans) != len(wantNames) {
+		t.Fatalf("expected %d spans but got %d", len(wantNames), len(spans))
+	}
+
+	for i, want := range wantNames {
+		s := spans[i]
+		if s.Name() != want {
+			t.Errorf("expected span %d to be %s but got %s", i, want, s.Name())
+		}
+
+		if got := s.SpanContext().TraceID().String(); got != traceID {
+			t.Errorf("expected span %s in trace %s but got %s", s.Name(), traceID, got)
+		}
+
+		if i > 0 && spans[i-1].Parent().SpanID() != s.SpanContext().SpanID() {
+			t.Errorf("expected %s to be the parent of %s", s.Name(), spans[i-1].Name())
+		}
+	}
+}